@@ -6,6 +6,7 @@ import (
 	"math"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -77,6 +78,85 @@ func Get(s string) (AssertFunc, bool) {
 	return f, ok
 }
 
+// Names returns the sorted names of the assertions
+func Names() []string {
+	names := make([]string, 0, len(assertMap))
+	for name := range assertMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// arity is the minimum and the maximum numbers of comparison values of an assertion
+type arity struct {
+	min int
+	max int
+}
+
+// assertArity contains the numbers of comparison values of the assertions, a max of -1 is unbounded
+var assertArity = map[string]arity{
+	"ShouldEqual":                  {1, -1},
+	"ShouldNotEqual":               {1, -1},
+	"ShouldAlmostEqual":            {2, 2},
+	"ShouldNotAlmostEqual":         {2, 2},
+	"ShouldNotExist":               {0, 0},
+	"ShouldBeNil":                  {0, 0},
+	"ShouldNotBeNil":               {0, 0},
+	"ShouldBeTrue":                 {0, 0},
+	"ShouldBeFalse":                {0, 0},
+	"ShouldBeZeroValue":            {0, 0},
+	"ShouldBeGreaterThan":          {1, 1},
+	"ShouldBeGreaterThanOrEqualTo": {1, 1},
+	"ShouldBeLessThan":             {1, 1},
+	"ShouldBeLessThanOrEqualTo":    {1, 1},
+	"ShouldBeBetween":              {2, 2},
+	"ShouldNotBeBetween":           {2, 2},
+	"ShouldBeBetweenOrEqual":       {2, 2},
+	"ShouldNotBeBetweenOrEqual":    {2, 2},
+	"ShouldContain":                {1, 1},
+	"ShouldNotContain":             {1, 1},
+	"ShouldJSONContain":            {1, 1},
+	"ShouldNotJSONContain":         {1, 1},
+	"ShouldJSONContainWithKey":     {2, 2},
+	"ShouldJSONContainAllWithKey":  {2, 2},
+	"ShouldNotJSONContainWithKey":  {2, 2},
+	"ShouldContainKey":             {1, 1},
+	"ShouldNotContainKey":          {1, 1},
+	"ShouldBeIn":                   {1, -1},
+	"ShouldNotBeIn":                {1, -1},
+	"ShouldBeEmpty":                {0, 0},
+	"ShouldNotBeEmpty":             {0, 0},
+	"ShouldHaveLength":             {1, 1},
+	"ShouldStartWith":              {1, 1},
+	"ShouldNotStartWith":           {1, 1},
+	"ShouldEndWith":                {1, 1},
+	"ShouldNotEndWith":             {1, 1},
+	"ShouldBeBlank":                {0, 0},
+	"ShouldNotBeBlank":             {0, 0},
+	"ShouldContainSubstring":       {1, -1},
+	"ShouldNotContainSubstring":    {1, -1},
+	"ShouldEqualTrimSpace":         {1, -1},
+	"ShouldHappenBefore":           {1, 1},
+	"ShouldHappenOnOrBefore":       {1, 1},
+	"ShouldHappenAfter":            {1, 1},
+	"ShouldHappenOnOrAfter":        {1, 1},
+	"ShouldHappenBetween":          {2, 2},
+	"ShouldTimeEqual":              {1, 1},
+	"ShouldJSONEqual":              {1, 1},
+	"ShouldNotJSONEqual":           {1, 1},
+	"ShouldBeArray":                {0, 0},
+	"ShouldBeMap":                  {0, 0},
+	"ShouldMatchRegex":             {1, 1},
+}
+
+// Arity returns the minimum and the maximum numbers of comparison values of an assertion,
+// the maximum is -1 if the number of values is unbounded
+func Arity(s string) (int, int, bool) {
+	a, ok := assertArity[s]
+	return a.min, a.max, ok
+}
+
 func deepEqual(x, y interface{}) bool {
 	if !reflect.DeepEqual(x, y) {
 		return fmt.Sprintf("%v", x) == fmt.Sprintf("%v", y)
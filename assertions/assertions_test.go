@@ -2438,3 +2438,30 @@ func TestShouldNotJSONEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestArity(t *testing.T) {
+	for name := range assertMap {
+		_, _, ok := Arity(name)
+		assert.True(t, ok, "missing arity of %s", name)
+	}
+
+	min, max, ok := Arity("ShouldBeNil")
+	assert.True(t, ok)
+	assert.Equal(t, 0, min)
+	assert.Equal(t, 0, max)
+
+	min, max, ok = Arity("ShouldEqual")
+	assert.True(t, ok)
+	assert.Equal(t, 1, min)
+	assert.Equal(t, -1, max)
+
+	_, _, ok = Arity("ShouldBeFoo")
+	assert.False(t, ok)
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	assert.Len(t, names, len(assertMap))
+	assert.Contains(t, names, "ShouldEqual")
+	assert.IsIncreasing(t, names)
+}
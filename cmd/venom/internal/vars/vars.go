@@ -0,0 +1,106 @@
+// Package vars reads the variables given to the venom commands, by the --var and --var-from-file flags and by the environment
+package vars
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rockbears/yaml"
+
+	"github.com/ovh/venom"
+	"github.com/ovh/venom/interpolate"
+)
+
+func cast(vS string) interface{} {
+	var v interface{}
+	_ = yaml.Unmarshal([]byte(vS), &v) //nolint
+	return v
+}
+
+// FromEnv returns the variables of the VENOM_VAR environment variable, separated by spaces, followed by the ones of the VENOM_VAR_* environment variables,
+// as name=value, and the files of the VENOM_VAR_FROM_FILE environment variable
+func FromEnv(environ []string) ([]string, []string) {
+	var variables, prefixed, varFiles []string
+	for _, env := range environ {
+		tuple := strings.SplitN(env, "=", 2)
+		if len(tuple) < 2 {
+			continue
+		}
+		switch {
+		case tuple[0] == "VENOM_VAR" && tuple[1] != "":
+			variables = strings.Split(tuple[1], " ")
+		case tuple[0] == "VENOM_VAR_FROM_FILE" && tuple[1] != "":
+			varFiles = strings.Split(tuple[1], " ")
+		case strings.HasPrefix(tuple[0], "VENOM_VAR_"):
+			k := strings.TrimPrefix(tuple[0], "VENOM_VAR_")
+			prefixed = append(prefixed, fmt.Sprintf("%v=%v", k, cast(tuple[1])))
+		}
+	}
+	return append(variables, prefixed...), varFiles
+}
+
+// Read returns the variables of the files, then the ones of the arguments name=value, the latter ones taking precedence.
+// The values are yaml, interpolated without variables.
+func Read(ctx context.Context, args []string, files []string) (map[string]interface{}, error) {
+	readers := []io.Reader{}
+	for _, f := range files {
+		if f == "" {
+			continue
+		}
+		fi, err := os.Open(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open var-from-file %s: %v", f, err)
+		}
+		defer fi.Close()
+		readers = append(readers, fi)
+	}
+	return read(ctx, args, readers)
+}
+
+func read(ctx context.Context, argsVars []string, argVarsFiles []io.Reader) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+
+	for _, r := range argVarsFiles {
+		tmpResult := map[string]interface{}{}
+		btes, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		stemp, err := interpolate.Do(string(btes), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to interpolate file")
+		}
+
+		if err := yaml.Unmarshal([]byte(stemp), &tmpResult); err != nil {
+			return nil, errors.Wrap(err, "unable to unmarshal file")
+		}
+
+		for k, v := range tmpResult {
+			result[k] = v
+			venom.Debug(ctx, "Adding variable from vars-files %s=%s", k, v)
+		}
+	}
+
+	for _, arg := range argsVars {
+		if arg == "" {
+			continue
+		}
+		tuple := strings.SplitN(arg, "=", 2)
+		if len(tuple) < 2 {
+			return nil, fmt.Errorf("invalid variable declaration: %v", arg)
+		}
+		stemp, err := interpolate.Do(tuple[1], nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to interpolate arg %s", arg)
+		}
+		result[tuple[0]] = cast(stemp)
+		venom.Debug(ctx, "Adding variable from vars arg %s=%s", tuple[0], result[tuple[0]])
+	}
+
+	return result, nil
+}
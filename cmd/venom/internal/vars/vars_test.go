@@ -0,0 +1,86 @@
+package vars
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/venom"
+)
+
+func Test_read(t *testing.T) {
+	venom.InitTestLogger(t)
+	type args struct {
+		argsVars     []string
+		argVarsFiles []io.Reader
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "from args",
+			args: args{
+				argsVars: []string{`a=1`, `b="B"`, `c=[1,2,3]`},
+			},
+			want: map[string]interface{}{
+				"a": 1.0,
+				"b": "B",
+				"c": []interface{}{1.0, 2.0, 3.0},
+			},
+		},
+		{
+			name: "from args",
+			args: args{
+				argsVars: []string{`db.dsn="user=test password=test dbname=yo host=localhost port=1234 sslmode=disable"`},
+			},
+			want: map[string]interface{}{
+				"db.dsn": "user=test password=test dbname=yo host=localhost port=1234 sslmode=disable",
+			},
+		},
+		{
+			name: "from readers",
+			args: args{
+				argVarsFiles: []io.Reader{
+					strings.NewReader(`
+a: 1
+b: B
+c:
+  - 1
+  - 2
+  - 3`),
+				},
+			},
+			want: map[string]interface{}{
+				"a": 1.0,
+				"b": "B",
+				"c": []interface{}{1.0, 2.0, 3.0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := read(context.TODO(), tt.args.argsVars, tt.args.argVarsFiles)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("read() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			require.EqualValues(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	variables, varFiles := FromEnv([]string{`VENOM_VAR_a=1`, `HOME=/root`, `VENOM_VAR=b=B c=C`, `VENOM_VAR_FROM_FILE=a.yml b.yml`})
+	require.Equal(t, []string{"b=B", "c=C", "a=1"}, variables)
+	require.Equal(t, []string{"a.yml", "b.yml"}, varFiles)
+
+	got, err := Read(context.TODO(), variables, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"a": 1.0, "b": "B", "c": "C"}, got)
+}
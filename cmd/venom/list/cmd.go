@@ -0,0 +1,148 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+	"github.com/ovh/venom/executors"
+)
+
+var (
+	format        string
+	libDir        string
+	listExecutors bool
+)
+
+func init() {
+	Cmd.Flags().StringVar(&format, "format", "tree", "Format of the list: tree, json")
+	Cmd.Flags().StringVar(&libDir, "lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
+	Cmd.Flags().BoolVar(&listExecutors, "executors", false, "List the builtin executors, the user executors and the plugins instead of the testsuites")
+}
+
+// Cmd list
+var Cmd = &cobra.Command{
+	Use:   "list [paths]",
+	Short: "List the testsuites and their testcases without running them",
+	Long: `List the testsuites and their testcases without running them:
+the number of steps of the testcases, the executors they use, their tags and their skip conditions.
+With --executors, list the builtin executors, the user executors of the lib directories with their inputs and outputs,
+and the plugins of the lib directories.`,
+	Example: `  List the testsuites of the current directory: venom list
+  Generate a test matrix: venom list tests/ --format json | jq -c '[.[].filepath]'
+  List the executors: venom list --executors --lib-dir tests/lib`,
+	// the errors are printed by main, the usage is not needed for errors about the testsuites
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if format != "tree" && format != "json" {
+			return fmt.Errorf("invalid format %q: tree or json expected", format)
+		}
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+
+		v := venom.New()
+		for name, executorFunc := range executors.Registry {
+			v.RegisterExecutorBuiltin(name, executorFunc())
+		}
+		v.LibDir = libDir
+		if v.LibDir == "" {
+			v.LibDir = os.Getenv("VENOM_LIB_DIR")
+		}
+
+		var list interface{}
+		var err error
+		if listExecutors {
+			list, err = v.ListExecutors(context.Background())
+		} else {
+			list, err = v.List(context.Background(), args)
+		}
+		if err != nil {
+			return err
+		}
+
+		if format == "json" {
+			btes, err := json.MarshalIndent(list, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(btes))
+			return nil
+		}
+		switch list := list.(type) {
+		case []venom.ListedExecutor:
+			return printExecutors(cmd.OutOrStdout(), list)
+		case []venom.ListedTestSuite:
+			printTestSuites(cmd.OutOrStdout(), list)
+		}
+		return nil
+	},
+}
+
+// printTestSuites prints the testsuites as a tree of their testcases
+func printTestSuites(w io.Writer, testSuites []venom.ListedTestSuite) {
+	for _, ts := range testSuites {
+		fmt.Fprintf(w, "%s (%s)%s\n", ts.Name, ts.Filepath, attributes("tags", ts.Tags))
+		var testCases []venom.ListedTestCase
+		if ts.Setup != nil {
+			testCases = append(testCases, *ts.Setup)
+		}
+		testCases = append(testCases, ts.TestCases...)
+		if ts.Teardown != nil {
+			testCases = append(testCases, *ts.Teardown)
+		}
+		for i, tc := range testCases {
+			branch := "├── "
+			if i == len(testCases)-1 {
+				branch = "└── "
+			}
+			steps := fmt.Sprintf("%d steps", tc.Steps)
+			if tc.Steps == 1 {
+				steps = "1 step"
+			}
+			if len(tc.Executors) > 0 {
+				steps += ": " + strings.Join(tc.Executors, ", ")
+			}
+			var ranged string
+			if tc.Range != nil {
+				ranged = " [ranged]"
+			}
+			fmt.Fprintf(w, "%s%s (%s)%s%s%s\n", branch, tc.Name, steps, ranged, attributes("tags", tc.Tags), attributes("skip", tc.Skip))
+		}
+	}
+}
+
+func attributes(name string, values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [%s: %s]", name, strings.Join(values, ", "))
+}
+
+// printExecutors prints a table of the executors
+func printExecutors(w io.Writer, list []venom.ListedExecutor) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tINPUTS\tOUTPUTS\tFILE")
+	for _, e := range list {
+		inputs := make([]string, 0, len(e.Inputs))
+		for k, value := range e.Inputs {
+			if value == nil {
+				inputs = append(inputs, k)
+			} else {
+				inputs = append(inputs, fmt.Sprintf("%s=%v", k, value))
+			}
+		}
+		sort.Strings(inputs)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.Name, e.Type, strings.Join(inputs, ", "), strings.Join(e.Outputs, ", "), e.File)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+)
+
+var (
+	format string
+	output string
+)
+
+func init() {
+	for _, c := range []*cobra.Command{mergeCmd, convertCmd} {
+		// the errors are printed by main, the usage is not needed for errors about the reports
+		c.SilenceErrors = true
+		c.SilenceUsage = true
+		c.Flags().StringVar(&format, "format", "xml", "Format of the report: "+strings.Join(venom.ReportFormats, ", "))
+		c.Flags().StringVarP(&output, "output", "o", "", "File to write the report to, the standard output if empty")
+	}
+	Cmd.AddCommand(mergeCmd)
+	Cmd.AddCommand(convertCmd)
+}
+
+// Cmd report
+var Cmd = &cobra.Command{
+	Use:   "report",
+	Short: "Merge and convert the json reports of venom runs",
+	Long:  `venom report merge|convert`,
+}
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <report.json|dir>...",
+	Short: "Merge json reports in a single report: venom report merge results/ --format html -o report.html",
+	Example: `  Merge the json reports of several shards in a xml report: venom report merge shard-1/ shard-2/ shard-3/ -o test_results.xml
+  Merge the json reports of a directory in a html report: venom report merge results/ --format html -o test_results.html`,
+	Long: `Merge json reports in a single report.
+A testsuite found in several reports is kept once: the one which started last.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var reports []venom.Tests
+		for _, path := range args {
+			r, err := venom.ReadReports(path)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, r...)
+		}
+		return writeReport(venom.MergeReports(reports))
+	},
+}
+
+var convertCmd = &cobra.Command{
+	Use:     "convert <report.json>",
+	Short:   "Convert a json report in another format: venom report convert test_results.json --format xml",
+	Example: `  Convert a json report in a html report: venom report convert test_results.json --format html -o test_results.html`,
+	Long:    `Convert a json report in another format, without running the testsuites again.`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if fi, err := os.Stat(args[0]); err == nil && fi.IsDir() {
+			return fmt.Errorf("%q is a directory, use venom report merge to combine its reports", args[0])
+		}
+		reports, err := venom.ReadReports(args[0])
+		if err != nil {
+			return err
+		}
+		return writeReport(&reports[0])
+	},
+}
+
+func writeReport(tests *venom.Tests) error {
+	data, err := venom.FormatReport(tests, format, 0)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0o600); err != nil {
+		return fmt.Errorf("Error while creating file %s: %v", output, err)
+	}
+	fmt.Fprintf(os.Stderr, "Writing file %s\n", output)
+	return nil
+}
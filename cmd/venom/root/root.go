@@ -3,8 +3,12 @@ package root
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ovh/venom/cmd/venom/list"
+	"github.com/ovh/venom/cmd/venom/report"
 	"github.com/ovh/venom/cmd/venom/run"
+	"github.com/ovh/venom/cmd/venom/schema"
 	"github.com/ovh/venom/cmd/venom/update"
+	"github.com/ovh/venom/cmd/venom/validate"
 	"github.com/ovh/venom/cmd/venom/version"
 )
 
@@ -21,6 +25,10 @@ func New() *cobra.Command {
 // AddCommands adds child commands to the root command rootCmd.
 func addCommands(cmd *cobra.Command) {
 	cmd.AddCommand(run.Cmd)
+	cmd.AddCommand(report.Cmd)
+	cmd.AddCommand(validate.Cmd)
+	cmd.AddCommand(schema.Cmd)
+	cmd.AddCommand(list.Cmd)
 	cmd.AddCommand(version.Cmd)
 	cmd.AddCommand(update.Cmd)
 }
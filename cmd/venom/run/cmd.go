@@ -2,13 +2,17 @@ package run
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime/pprof"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
@@ -17,8 +21,8 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/ovh/venom"
+	"github.com/ovh/venom/cmd/venom/internal/vars"
 	"github.com/ovh/venom/executors"
-	"github.com/ovh/venom/interpolate"
 )
 
 var (
@@ -32,8 +36,24 @@ var (
 	outputDir     string
 	libDir        string
 	htmlReport    bool
+	aggregate     bool
 	stopOnFailure bool
 	verbose       int = 0 // Set the default value for verboseFlag
+	parallel      int = 1
+	runFilter     string
+	tags          string
+	excludeTags   string
+	timeout       time.Duration
+	rerunFailed   string
+	rerunMerge    bool
+	repeat        int
+	repeatUntil   bool
+	quarantined   []venom.Quarantine
+	quarantine    string
+	shard         string
+	shardTimings  string
+	events        string
+	dryRun        bool
 
 	variablesFlag     *[]string
 	formatFlag        *string
@@ -42,18 +62,48 @@ var (
 	libDirFlag        *string
 	stopOnFailureFlag *bool
 	htmlReportFlag    *bool
+	aggregateFlag     *bool
 	verboseFlag       *int
+	parallelFlag      *int
+	runFilterFlag     *string
+	tagsFlag          *string
+	excludeTagsFlag   *string
+	timeoutFlag       *time.Duration
+	rerunFailedFlag   *string
+	rerunMergeFlag    *bool
+	repeatFlag        *int
+	repeatUntilFlag   *bool
+	quarantineFlag    *string
+	shardFlag         *string
+	shardTimingsFlag  *string
+	eventsFlag        *string
+	dryRunFlag        *bool
 )
 
 func init() {
-	formatFlag = Cmd.Flags().String("format", "xml", "--format:json, tap, xml, yaml")
+	formatFlag = Cmd.Flags().String("format", "xml", "--format:json, tap, xml, yaml, or a comma separated list of formats such as --format xml,json")
 	stopOnFailureFlag = Cmd.Flags().Bool("stop-on-failure", false, "Stop running Test Suite on first Test Case failure")
 	htmlReportFlag = Cmd.Flags().Bool("html-report", false, "Generate HTML Report")
+	aggregateFlag = Cmd.Flags().Bool("aggregate-report", false, "Write a report of all the testsuites per format, in addition to the report of each testsuite")
 	verboseFlag = Cmd.Flags().CountP("verbose", "v", "verbose. -v (INFO level in venom.log file), -vv to very verbose (DEBUG level) and -vvv to very verbose with CPU Profiling")
 	varFilesFlag = Cmd.Flags().StringSlice("var-from-file", []string{""}, "--var-from-file filename.yaml --var-from-file filename2.yaml: yaml, must contains a dictionary")
 	variablesFlag = Cmd.Flags().StringArray("var", nil, "--var cds='cds -f config.json' --var cds2='cds -f config.json'")
 	outputDirFlag = Cmd.PersistentFlags().String("output-dir", "", "Output Directory: create tests results file inside this directory")
 	libDirFlag = Cmd.PersistentFlags().String("lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
+	parallelFlag = Cmd.Flags().Int("parallel", 1, "Number of test suites to run in parallel")
+	runFilterFlag = Cmd.Flags().String("run", "", "Run only the testcases whose 'testsuite/testcase' name matches the regular expression. example: --run 'login.*'")
+	tagsFlag = Cmd.Flags().String("tags", "", "Run only the testcases with matching tags. example: --tags 'smoke,critical' or --tags 'payments && !slow'")
+	excludeTagsFlag = Cmd.Flags().String("exclude-tags", "", "Skip the testcases with matching tags. example: --exclude-tags slow")
+	timeoutFlag = Cmd.Flags().Duration("timeout", 0, "Timeout of the whole run: the running testcases fail and the remaining ones are skipped. example: --timeout 30m")
+	rerunFailedFlag = Cmd.Flags().String("rerun-failed", "", "Rerun only the failed testcases of a previous run, read from its json report or from the directory of its json reports. example: --rerun-failed results/")
+	rerunMergeFlag = Cmd.Flags().Bool("rerun-merge", false, "With --rerun-failed, merge the results of the rerun into the previous results")
+	repeatFlag = Cmd.Flags().Int("repeat", 0, "Run each testcase N times, to detect the flaky ones. example: --repeat 10")
+	repeatUntilFlag = Cmd.Flags().Bool("repeat-until-fail", false, "Stop repeating a testcase at its first failure. Without --repeat, the testcases are repeated until they fail")
+	quarantineFlag = Cmd.Flags().String("quarantine", "", "File listing the testcases in quarantine: they are run, but their failures do not fail the run (default quarantine.yml if it exists)")
+	shardFlag = Cmd.Flags().String("shard", "", "Run only the i-th of n shards of the testsuites, to split them across several jobs. example: --shard 3/8")
+	shardTimingsFlag = Cmd.Flags().String("shard-timings", "", "With --shard, balance the shards by the durations of the testsuites, read from the json report or the directory of the json reports of a previous run")
+	eventsFlag = Cmd.Flags().String("events", "", "Write the events of the run as newline-delimited json into a file, or to the standard output with -. example: --events events.ndjson")
+	dryRunFlag = Cmd.Flags().Bool("dry-run", false, "Print the interpolated steps without running their executors, the steps are also written in the json report")
 }
 
 func initArgs(cmd *cobra.Command) {
@@ -89,6 +139,10 @@ func initFromCommandArguments(f *pflag.Flag) {
 		if htmlReportFlag != nil {
 			htmlReport = *htmlReportFlag
 		}
+	case "aggregate-report":
+		if aggregateFlag != nil {
+			aggregate = *aggregateFlag
+		}
 	case "output-dir":
 		if outputDirFlag != nil {
 			outputDir = *outputDirFlag
@@ -101,6 +155,62 @@ func initFromCommandArguments(f *pflag.Flag) {
 		if verboseFlag != nil {
 			verbose = *verboseFlag
 		}
+	case "parallel":
+		if parallelFlag != nil {
+			parallel = *parallelFlag
+		}
+	case "run":
+		if runFilterFlag != nil {
+			runFilter = *runFilterFlag
+		}
+	case "tags":
+		if tagsFlag != nil {
+			tags = *tagsFlag
+		}
+	case "exclude-tags":
+		if excludeTagsFlag != nil {
+			excludeTags = *excludeTagsFlag
+		}
+	case "timeout":
+		if timeoutFlag != nil {
+			timeout = *timeoutFlag
+		}
+	case "rerun-failed":
+		if rerunFailedFlag != nil {
+			rerunFailed = *rerunFailedFlag
+		}
+	case "rerun-merge":
+		if rerunMergeFlag != nil {
+			rerunMerge = *rerunMergeFlag
+		}
+	case "repeat":
+		if repeatFlag != nil {
+			repeat = *repeatFlag
+		}
+	case "repeat-until-fail":
+		if repeatUntilFlag != nil {
+			repeatUntil = *repeatUntilFlag
+		}
+	case "quarantine":
+		if quarantineFlag != nil {
+			quarantine = *quarantineFlag
+		}
+	case "shard":
+		if shardFlag != nil {
+			shard = *shardFlag
+		}
+	case "shard-timings":
+		if shardTimingsFlag != nil {
+			shardTimings = *shardTimingsFlag
+		}
+	case "events":
+		if eventsFlag != nil {
+			events = *eventsFlag
+		}
+	case "dry-run":
+		if dryRunFlag != nil {
+			dryRun = *dryRunFlag
+		}
 	case "var-from-file":
 		if varFilesFlag != nil {
 			for _, varFile := range *varFilesFlag {
@@ -152,15 +262,38 @@ func initFromConfigFile() error {
 }
 
 type ConfigFileData struct {
-	Format         *string   `json:"format,omitempty" yaml:"format,omitempty"`
-	LibDir         *string   `json:"lib_dir,omitempty" yaml:"lib_dir,omitempty"`
-	OutputDir      *string   `json:"output_dir,omitempty" yaml:"output_dir,omitempty"`
-	StopOnFailure  *bool     `json:"stop_on_failure,omitempty" yaml:"stop_on_failure,omitempty"`
-	HtmlReport     *bool     `json:"html_report,omitempty" yaml:"html_report,omitempty"`
-	Variables      *[]string `json:"variables,omitempty" yaml:"variables,omitempty"`
-	Secrets        *[]string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
-	VariablesFiles *[]string `json:"variables_files,omitempty" yaml:"variables_files,omitempty"`
-	Verbosity      *int      `json:"verbosity,omitempty" yaml:"verbosity,omitempty"`
+	Format          *formatList         `json:"format,omitempty" yaml:"format,omitempty"`
+	LibDir          *string             `json:"lib_dir,omitempty" yaml:"lib_dir,omitempty"`
+	OutputDir       *string             `json:"output_dir,omitempty" yaml:"output_dir,omitempty"`
+	StopOnFailure   *bool               `json:"stop_on_failure,omitempty" yaml:"stop_on_failure,omitempty"`
+	HtmlReport      *bool               `json:"html_report,omitempty" yaml:"html_report,omitempty"`
+	AggregateReport *bool               `json:"aggregate_report,omitempty" yaml:"aggregate_report,omitempty"`
+	Variables       *[]string           `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Secrets         *[]string           `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	VariablesFiles  *[]string           `json:"variables_files,omitempty" yaml:"variables_files,omitempty"`
+	Verbosity       *int                `json:"verbosity,omitempty" yaml:"verbosity,omitempty"`
+	Parallel        *int                `json:"parallel,omitempty" yaml:"parallel,omitempty"`
+	Tags            *string             `json:"tags,omitempty" yaml:"tags,omitempty"`
+	ExcludeTags     *string             `json:"exclude_tags,omitempty" yaml:"exclude_tags,omitempty"`
+	Timeout         *venom.Duration     `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Quarantine      *[]venom.Quarantine `json:"quarantine,omitempty" yaml:"quarantine,omitempty"`
+}
+
+// formatList is the format of the configuration file: a format, a comma separated list of formats, or a list of formats
+type formatList []string
+
+func (l *formatList) UnmarshalJSON(data []byte) error {
+	var format string
+	if err := json.Unmarshal(data, &format); err == nil {
+		*l = strings.Split(format, ",")
+		return nil
+	}
+	var formats []string
+	if err := json.Unmarshal(data, &formats); err != nil {
+		return fmt.Errorf("invalid format %s, must be a format or a list of formats", string(data))
+	}
+	*l = formats
+	return nil
 }
 
 // Configuration file overrides the environment variables.
@@ -177,7 +310,7 @@ func initFromReaderConfigFile(reader io.Reader) error {
 	}
 
 	if configFileData.Format != nil {
-		format = *configFileData.Format
+		format = strings.Join(*configFileData.Format, ",")
 	}
 	if configFileData.LibDir != nil {
 		libDir = *configFileData.LibDir
@@ -191,6 +324,9 @@ func initFromReaderConfigFile(reader io.Reader) error {
 	if configFileData.HtmlReport != nil {
 		htmlReport = *configFileData.HtmlReport
 	}
+	if configFileData.AggregateReport != nil {
+		aggregate = *configFileData.AggregateReport
+	}
 	if configFileData.Variables != nil {
 		for _, varFromFile := range *configFileData.Variables {
 			variables = mergeVariables(varFromFile, variables)
@@ -209,6 +345,21 @@ func initFromReaderConfigFile(reader io.Reader) error {
 	if configFileData.Verbosity != nil {
 		verbose = *configFileData.Verbosity
 	}
+	if configFileData.Parallel != nil {
+		parallel = *configFileData.Parallel
+	}
+	if configFileData.Tags != nil {
+		tags = *configFileData.Tags
+	}
+	if configFileData.ExcludeTags != nil {
+		excludeTags = *configFileData.ExcludeTags
+	}
+	if configFileData.Timeout != nil {
+		timeout = time.Duration(*configFileData.Timeout)
+	}
+	if configFileData.Quarantine != nil {
+		quarantined = *configFileData.Quarantine
+	}
 
 	return nil
 }
@@ -240,13 +391,12 @@ func isInArray(elt string, array []string) bool {
 }
 
 func initFromEnv(environ []string) ([]string, error) {
-	if os.Getenv("VENOM_VAR") != "" {
-		v := strings.Split(os.Getenv("VENOM_VAR"), " ")
-		variables = v
+	envVariables, envVarFiles := vars.FromEnv(environ)
+	if len(envVariables) > 0 {
+		variables = envVariables
 	}
-
-	if os.Getenv("VENOM_VAR_FROM_FILE") != "" {
-		varFiles = strings.Split(os.Getenv("VENOM_VAR_FROM_FILE"), " ")
+	if len(envVarFiles) > 0 {
+		varFiles = envVarFiles
 	}
 	if os.Getenv("VENOM_FORMAT") != "" {
 		format = os.Getenv("VENOM_FORMAT")
@@ -265,6 +415,13 @@ func initFromEnv(environ []string) ([]string, error) {
 			return nil, fmt.Errorf("invalid value for VENOM_HTML_REPORT")
 		}
 	}
+	if os.Getenv("VENOM_AGGREGATE_REPORT") != "" {
+		var err error
+		aggregate, err = strconv.ParseBool(os.Getenv("VENOM_AGGREGATE_REPORT"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for VENOM_AGGREGATE_REPORT")
+		}
+	}
 	if os.Getenv("VENOM_LIB_DIR") != "" {
 		libDir = os.Getenv("VENOM_LIB_DIR")
 	}
@@ -279,18 +436,70 @@ func initFromEnv(environ []string) ([]string, error) {
 		v2 := int(v)
 		verbose = v2
 	}
-
-	cast := func(vS string) interface{} {
-		var v interface{}
-		_ = yaml.Unmarshal([]byte(vS), &v) //nolint
-		return v
+	if os.Getenv("VENOM_PARALLEL") != "" {
+		v, err := strconv.Atoi(os.Getenv("VENOM_PARALLEL"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for VENOM_PARALLEL, must be an integer")
+		}
+		parallel = v
 	}
-
-	for _, env := range environ {
-		if strings.HasPrefix(env, "VENOM_VAR_") {
-			tuple := strings.SplitN(env, "=", 2)
-			k := strings.TrimPrefix(tuple[0], "VENOM_VAR_")
-			variables = append(variables, fmt.Sprintf("%v=%v", k, cast(tuple[1])))
+	if os.Getenv("VENOM_RUN") != "" {
+		runFilter = os.Getenv("VENOM_RUN")
+	}
+	if os.Getenv("VENOM_TAGS") != "" {
+		tags = os.Getenv("VENOM_TAGS")
+	}
+	if os.Getenv("VENOM_EXCLUDE_TAGS") != "" {
+		excludeTags = os.Getenv("VENOM_EXCLUDE_TAGS")
+	}
+	if os.Getenv("VENOM_TIMEOUT") != "" {
+		var err error
+		timeout, err = time.ParseDuration(os.Getenv("VENOM_TIMEOUT"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for VENOM_TIMEOUT, must be a duration such as 30m")
+		}
+	}
+	if os.Getenv("VENOM_RERUN_FAILED") != "" {
+		rerunFailed = os.Getenv("VENOM_RERUN_FAILED")
+	}
+	if os.Getenv("VENOM_RERUN_MERGE") != "" {
+		var err error
+		rerunMerge, err = strconv.ParseBool(os.Getenv("VENOM_RERUN_MERGE"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for VENOM_RERUN_MERGE")
+		}
+	}
+	if os.Getenv("VENOM_REPEAT") != "" {
+		v, err := strconv.Atoi(os.Getenv("VENOM_REPEAT"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for VENOM_REPEAT, must be an integer")
+		}
+		repeat = v
+	}
+	if os.Getenv("VENOM_REPEAT_UNTIL_FAIL") != "" {
+		var err error
+		repeatUntil, err = strconv.ParseBool(os.Getenv("VENOM_REPEAT_UNTIL_FAIL"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for VENOM_REPEAT_UNTIL_FAIL")
+		}
+	}
+	if os.Getenv("VENOM_QUARANTINE") != "" {
+		quarantine = os.Getenv("VENOM_QUARANTINE")
+	}
+	if os.Getenv("VENOM_SHARD") != "" {
+		shard = os.Getenv("VENOM_SHARD")
+	}
+	if os.Getenv("VENOM_SHARD_TIMINGS") != "" {
+		shardTimings = os.Getenv("VENOM_SHARD_TIMINGS")
+	}
+	if os.Getenv("VENOM_EVENTS") != "" {
+		events = os.Getenv("VENOM_EVENTS")
+	}
+	if os.Getenv("VENOM_DRY_RUN") != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(os.Getenv("VENOM_DRY_RUN"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for VENOM_DRY_RUN")
 		}
 	}
 
@@ -303,8 +512,23 @@ func displayArg(ctx context.Context) {
 	venom.Debug(ctx, "option outputDir=%v", outputDir)
 	venom.Debug(ctx, "option stopOnFailure=%v", stopOnFailure)
 	venom.Debug(ctx, "option htmlReport=%v", htmlReport)
+	venom.Debug(ctx, "option aggregateReport=%v", aggregate)
 	venom.Debug(ctx, "option varFiles=%v", strings.Join(varFiles, " "))
 	venom.Debug(ctx, "option verbose=%v", verbose)
+	venom.Debug(ctx, "option parallel=%v", parallel)
+	venom.Debug(ctx, "option run=%v", runFilter)
+	venom.Debug(ctx, "option tags=%v", tags)
+	venom.Debug(ctx, "option excludeTags=%v", excludeTags)
+	venom.Debug(ctx, "option timeout=%v", timeout)
+	venom.Debug(ctx, "option rerunFailed=%v", rerunFailed)
+	venom.Debug(ctx, "option rerunMerge=%v", rerunMerge)
+	venom.Debug(ctx, "option repeat=%v", repeat)
+	venom.Debug(ctx, "option repeatUntilFail=%v", repeatUntil)
+	venom.Debug(ctx, "option quarantine=%v", quarantine)
+	venom.Debug(ctx, "option shard=%v", shard)
+	venom.Debug(ctx, "option shardTimings=%v", shardTimings)
+	venom.Debug(ctx, "option events=%v", events)
+	venom.Debug(ctx, "option dryRun=%v", dryRun)
 }
 
 // Cmd run
@@ -315,9 +539,20 @@ var Cmd = &cobra.Command{
   Run a single testsuite: venom run mytestfile.yml
   Run a single testsuite and export the result in JSON format in test/ folder: venom run mytestfile.yml --format=json --output-dir=test
   Run a single testsuite and export the result in XML and HTML formats in test/ folder: venom run mytestfile.yml --format=xml --output-dir=test --html-report
+  Run all testsuites and export the results in XML and JSON formats, with a report of all the testsuites per format: venom run --format=xml,json --aggregate-report --output-dir=test
   Run a single testsuite and specify a variable: venom run mytestfile.yml --var="foo=bar"
   Run a single testsuite and load all variables from a file: venom run mytestfile.yml --var-from-file variables.yaml
   Run all testsuites containing in files ending with *.yml or *.yaml with verbosity: VENOM_VERBOSE=2 venom run
+  Run all testsuites containing in files ending with *.yml or *.yaml, 4 testsuites at a time: venom run --parallel 4
+  Run only the testcases whose name starts with login: venom run --run '/login'
+  Run only the smoke testcases, except the slow ones: venom run --tags smoke --exclude-tags slow
+  Run all testsuites, failing the run if it lasts more than 30 minutes: venom run --timeout 30m
+  Rerun only the testcases which failed in a previous run, and merge the results: venom run --rerun-failed results/ --rerun-merge --format=json --output-dir=results-rerun
+  Run each testcase 20 times to find the flaky ones: venom run --repeat 20
+  Run all testsuites, the failures of the testcases listed in a quarantine file not failing the run: venom run --quarantine flaky.yml
+  Run the third of eight shards of the testsuites, balanced by the durations of a previous run: venom run --shard 3/8 --shard-timings results/
+  Run all testsuites, writing the events of the run to follow its progress: venom run --events events.ndjson
+  Print the interpolated steps of a testsuite without running them: venom run mytestfile.yml --dry-run
   
   Notice that variables initialized with -var-from-file argument can be overrided with -var argument
   
@@ -343,13 +578,64 @@ var Cmd = &cobra.Command{
 		v.OutputFormat = format
 		v.StopOnFailure = stopOnFailure
 		v.HtmlReport = htmlReport
+		v.AggregateReport = aggregate
 		v.Verbose = verbose
+		v.Parallel = parallel
+		v.RunFilter = runFilter
+		v.Tags = tags
+		v.ExcludeTags = excludeTags
+		v.Timeout = timeout
+		v.RerunFailed = rerunFailed
+		v.RerunMerge = rerunMerge
+		v.Repeat = repeat
+		v.RepeatUntilFail = repeatUntil
+		v.DryRun = dryRun
+		v.Quarantine = quarantined
+		if quarantine == "" && fileExists("quarantine.yml") {
+			quarantine = "quarantine.yml"
+		}
+		if quarantine != "" {
+			q, err := venom.ReadQuarantineFile(quarantine)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				venom.OSExit(2)
+			}
+			v.Quarantine = append(v.Quarantine, q...)
+			v.QuarantineFile = quarantine
+		}
+		if shard != "" {
+			var err error
+			if v.Shard, err = venom.ParseShard(shard); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				venom.OSExit(2)
+			}
+			v.ShardTimings = shardTimings
+		}
+
+		// the events written to the standard output must not be mixed with the output of the run
+		out := io.Writer(os.Stdout)
+		if events == "-" {
+			out = os.Stderr
+		}
+		v.Output = out
 
 		if err := v.InitLogger(); err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			venom.OSExit(2)
 		}
 
+		if events == "-" {
+			v.Events = os.Stdout
+		} else if events != "" {
+			fi, err := os.Create(events)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "unable to create events file %s: %v\n", events, err)
+				venom.OSExit(2)
+			}
+			defer fi.Close()
+			v.Events = fi
+		}
+
 		if v.Verbose == 3 {
 			fCPU, err := os.Create(filepath.Join(v.OutputDir, "pprof_cpu_profile.prof"))
 			if err != nil {
@@ -372,99 +658,102 @@ var Cmd = &cobra.Command{
 			displayArg(context.Background())
 		}
 
-		readers := []io.Reader{}
-		for _, f := range varFiles {
-			if f == "" {
-				continue
-			}
-			fi, err := os.Open(f)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "unable to open var-from-file %s: %v\n", f, err)
-				venom.OSExit(2)
-			}
-			defer fi.Close()
-			readers = append(readers, fi)
-		}
-
-		mapvars, err := readInitialVariables(context.Background(), variables, readers, os.Environ())
+		mapvars, err := vars.Read(context.Background(), variables, varFiles)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			venom.OSExit(2)
 		}
 		v.AddVariables(mapvars)
 
-		if err := v.Parse(context.Background(), path); err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
-			venom.OSExit(2)
-		}
+		ctx, stop := notifyInterruption()
+		defer stop()
 
-		if err := v.Process(context.Background(), path); err != nil {
+		if _, err := v.Run(ctx, path); err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			venom.OSExit(2)
 		}
 
+		// the partial results are written when venom is interrupted
 		if err := v.OutputResult(); err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			venom.OSExit(2)
 		}
 
+		printQuarantineSummary(out, v.Tests)
+
+		var interrupted venom.InterruptedError
+		if errors.As(context.Cause(ctx), &interrupted) {
+			fmt.Fprintf(out, "final status: %v (%v)\n", venom.Red(v.Tests.Status), interrupted)
+			venom.OSExit(signalExitCode(interrupted.Signal))
+		}
+
 		if v.Tests.Status == venom.StatusPass {
-			fmt.Fprintf(os.Stdout, "final status: %v\n", venom.Green(v.Tests.Status))
+			fmt.Fprintf(out, "final status: %v\n", venom.Green(v.Tests.Status))
 			venom.OSExit(0)
 		}
-		fmt.Fprintf(os.Stdout, "final status: %v\n", venom.Red(v.Tests.Status))
+		fmt.Fprintf(out, "final status: %v\n", venom.Red(v.Tests.Status))
 		venom.OSExit(2)
 
 		return nil
 	},
 }
 
-func readInitialVariables(ctx context.Context, argsVars []string, argVarsFiles []io.Reader, environ []string) (map[string]interface{}, error) {
-	cast := func(vS string) interface{} {
-		var v interface{}
-		_ = yaml.Unmarshal([]byte(vS), &v) //nolint
-		return v
-	}
-
-	result := map[string]interface{}{}
-
-	for _, r := range argVarsFiles {
-		tmpResult := map[string]interface{}{}
-		btes, err := io.ReadAll(r)
-		if err != nil {
-			return nil, err
-		}
-
-		stemp, err := interpolate.Do(string(btes), nil)
-		if err != nil {
-			return nil, errors.Wrap(err, "unable to interpolate file")
-		}
-
-		if err := yaml.Unmarshal([]byte(stemp), &tmpResult); err != nil {
-			return nil, errors.Wrap(err, "unable to unmarshal file")
-		}
-
-		for k, v := range tmpResult {
-			result[k] = v
-			venom.Debug(ctx, "Adding variable from vars-files %s=%s", k, v)
+// printQuarantineSummary lists the testcases in quarantine which have run, so that they are not forgotten
+func printQuarantineSummary(w io.Writer, tests venom.Tests) {
+	var lines []string
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			if tc.Status != venom.StatusQuarantined {
+				continue
+			}
+			line := fmt.Sprintf("  • %s/%s: %s", ts.Name, tc.Name, tc.Quarantine.Status)
+			var details []string
+			if tc.Quarantine.Owner != "" {
+				details = append(details, "owner: "+tc.Quarantine.Owner)
+			}
+			if tc.Quarantine.Ticket != "" {
+				details = append(details, "ticket: "+tc.Quarantine.Ticket)
+			}
+			if len(details) > 0 {
+				line += " (" + strings.Join(details, ", ") + ")"
+			}
+			lines = append(lines, line)
 		}
 	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%d testcase(s) in quarantine:\n%s\n", len(lines), strings.Join(lines, "\n"))
+}
 
-	for _, arg := range argsVars {
-		if arg == "" {
-			continue
-		}
-		tuple := strings.SplitN(arg, "=", 2)
-		if len(tuple) < 2 {
-			return nil, fmt.Errorf("invalid variable declaration: %v", arg)
-		}
-		stemp, err := interpolate.Do(tuple[1], nil)
-		if err != nil {
-			return nil, errors.Wrapf(err, "unable to interpolate arg %s", arg)
-		}
-		result[tuple[0]] = cast(stemp)
-		venom.Debug(ctx, "Adding variable from vars arg %s=%s", tuple[0], result[tuple[0]])
+// notifyInterruption returns a context cancelled on SIGINT or SIGTERM, with a venom.InterruptedError as cause.
+// A second signal exits at once.
+func notifyInterruption() (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig, ok := <-signals
+		if !ok {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "received signal %v, stopping: press Ctrl-C again to exit at once\n", sig)
+		cancel(venom.InterruptedError{Signal: sig})
+		if sig, ok = <-signals; ok {
+			os.Exit(signalExitCode(sig))
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		close(signals)
+		cancel(nil)
 	}
+}
 
-	return result, nil
+// signalExitCode returns the exit code of a process killed by a signal, as shells do
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 128
 }
@@ -1,8 +1,11 @@
 package run
 
 import (
-	"context"
+	"bufio"
+	"encoding/json"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -11,71 +14,6 @@ import (
 	"github.com/ovh/venom"
 )
 
-func Test_readInitialVariables(t *testing.T) {
-	venom.InitTestLogger(t)
-	type args struct {
-		argsVars     []string
-		argVarsFiles []io.Reader
-		env          []string
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    map[string]interface{}
-		wantErr bool
-	}{
-		{
-			name: "from args",
-			args: args{
-				argsVars: []string{`a=1`, `b="B"`, `c=[1,2,3]`},
-			},
-			want: map[string]interface{}{
-				"a": 1.0,
-				"b": "B",
-				"c": []interface{}{1.0, 2.0, 3.0},
-			},
-		},
-		{
-			name: "from args",
-			args: args{
-				argsVars: []string{`db.dsn="user=test password=test dbname=yo host=localhost port=1234 sslmode=disable"`},
-			},
-			want: map[string]interface{}{
-				"db.dsn": "user=test password=test dbname=yo host=localhost port=1234 sslmode=disable",
-			},
-		},
-		{
-			name: "from readers",
-			args: args{
-				argVarsFiles: []io.Reader{
-					strings.NewReader(`
-a: 1
-b: B
-c:
-  - 1
-  - 2
-  - 3`),
-				},
-			},
-			want: map[string]interface{}{
-				"a": 1.0,
-				"b": "B",
-				"c": []interface{}{1.0, 2.0, 3.0},
-			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := readInitialVariables(context.TODO(), tt.args.argsVars, tt.args.argVarsFiles, tt.args.env)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("readInitialVariables() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			require.EqualValues(t, tt.want, got)
-		})
-	}
-}
-
 func Test_mergeVariables(t *testing.T) {
 	ma := mergeVariables("aa=bb", []string{"cc=dd", "ee=ff"})
 	require.Equal(t, 3, len(ma))
@@ -108,3 +46,64 @@ func Test_initFromEnv(t *testing.T) {
 		}
 	}
 }
+
+func Test_initFromReaderConfigFileFormat(t *testing.T) {
+	defer func(previous string) { format = previous }(format)
+	for content, want := range map[string]string{
+		"format: json":             "json",
+		"format: xml,json":         "xml,json",
+		"format: [xml, json, tap]": "xml,json,tap",
+	} {
+		require.NoError(t, initFromReaderConfigFile(strings.NewReader(content)), content)
+		require.Equal(t, want, format, content)
+	}
+	require.Error(t, initFromReaderConfigFile(strings.NewReader("format: {xml: true}")))
+}
+
+func TestRunEventsStdout(t *testing.T) {
+	dir := t.TempDir()
+	testSuite := filepath.Join(dir, "events.yml")
+	require.NoError(t, os.WriteFile(testSuite, []byte(`name: events
+testcases:
+- name: echo
+  steps:
+  - type: exec
+    script: echo foo
+`), 0o644))
+
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	require.NoError(t, err)
+	defer stdout.Close()
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	require.NoError(t, err)
+	defer stderr.Close()
+
+	defer func(stdout, stderr *os.File, isTest string) {
+		os.Stdout, os.Stderr, venom.IsTest = stdout, stderr, isTest
+		events, outputDir = "", ""
+	}(os.Stdout, os.Stderr, venom.IsTest)
+	os.Stdout, os.Stderr, venom.IsTest = stdout, stderr, "test"
+
+	Cmd.SetArgs([]string{"--events", "-", "--output-dir", filepath.Join(dir, "results"), testSuite})
+	require.NoError(t, Cmd.Execute())
+
+	// the standard output contains only the events
+	_, err = stdout.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	var types []venom.EventType
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		var e venom.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e), scanner.Text())
+		types = append(types, e.Type)
+	}
+	require.NoError(t, scanner.Err())
+	require.NotEmpty(t, types)
+	require.Equal(t, venom.EventSuiteStart, types[0])
+	require.Equal(t, venom.EventRunEnd, types[len(types)-1])
+
+	// the output of the run is written to the standard error
+	btes, err := os.ReadFile(stderr.Name())
+	require.NoError(t, err)
+	require.Contains(t, string(btes), "final status")
+}
@@ -0,0 +1,65 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+	"github.com/ovh/venom/executors"
+)
+
+var (
+	output string
+	libDir string
+)
+
+func init() {
+	Cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the schema to, the standard output if empty")
+	Cmd.Flags().StringVar(&libDir, "lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
+}
+
+// Cmd schema
+var Cmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Generate the JSON Schema of the testsuites: venom schema -o venom.schema.json",
+	Long: `Generate the JSON Schema of the testsuites, for the editors to validate and complete them.
+The attributes of the steps depend on their type: the builtin executors and the user executors of the lib directories are described.`,
+	Example: `  Generate the schema with the user executors of a lib directory: venom schema --lib-dir tests/lib -o venom.schema.json`,
+	Args:    cobra.NoArgs,
+	// the errors are printed by main, the usage is not needed for errors about the user executors
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v := venom.New()
+		for name, executorFunc := range executors.Registry {
+			v.RegisterExecutorBuiltin(name, executorFunc())
+		}
+		v.LibDir = libDir
+		if v.LibDir == "" {
+			v.LibDir = os.Getenv("VENOM_LIB_DIR")
+		}
+
+		s, err := v.Schema(context.Background())
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		if output == "" {
+			_, err := cmd.OutOrStdout().Write(data)
+			return err
+		}
+		if err := os.WriteFile(output, data, 0o644); err != nil {
+			return fmt.Errorf("Error while creating file %s: %v", output, err)
+		}
+		fmt.Fprintf(os.Stderr, "Writing file %s\n", output)
+		return nil
+	},
+}
@@ -0,0 +1,94 @@
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+	"github.com/ovh/venom/cmd/venom/internal/vars"
+	"github.com/ovh/venom/executors"
+)
+
+var (
+	format    string
+	libDir    string
+	variables []string
+	varFiles  []string
+)
+
+func init() {
+	Cmd.Flags().StringVar(&format, "format", "text", "Format of the problems found: text, json")
+	Cmd.Flags().StringVar(&libDir, "lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
+	Cmd.Flags().StringArrayVar(&variables, "var", nil, "--var cds='cds -f config.json' --var cds2='cds -f config.json'")
+	Cmd.Flags().StringSliceVar(&varFiles, "var-from-file", nil, "--var-from-file filename.yaml --var-from-file filename2.yaml: yaml, must contains a dictionary")
+}
+
+// Cmd validate
+var Cmd = &cobra.Command{
+	Use:   "validate [paths]",
+	Short: "Check the testsuites and the user executors without running them",
+	Long: `Check the testsuites and the user executors without running them:
+the executors of the steps and their attributes, the operators of the assertions and their values,
+and the variables which are neither defined nor computed by a step.
+Each problem is printed as file:line:column: message, or as json with --format json.`,
+	Example: `  Check the testsuites of the current directory: venom validate
+  Check a testsuite with the variables of the run: venom validate --var-from-file vars.yml tests/api.yml --format json`,
+	// the errors are printed by main, the usage is not needed for the problems found
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if format != "text" && format != "json" {
+			return fmt.Errorf("invalid format %q: text or json expected", format)
+		}
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+
+		v := venom.New()
+		for name, executorFunc := range executors.Registry {
+			v.RegisterExecutorBuiltin(name, executorFunc())
+		}
+		v.LibDir = libDir
+		if v.LibDir == "" {
+			v.LibDir = os.Getenv("VENOM_LIB_DIR")
+		}
+		// the variables are read as venom run does, the flags taking precedence over the environment
+		envVariables, envVarFiles := vars.FromEnv(os.Environ())
+		if len(varFiles) == 0 {
+			varFiles = envVarFiles
+		}
+		mapvars, err := vars.Read(context.Background(), append(envVariables, variables...), varFiles)
+		if err != nil {
+			return err
+		}
+		v.AddVariables(mapvars)
+
+		diagnostics, err := v.Validate(context.Background(), args)
+		if err != nil {
+			return err
+		}
+
+		if format == "json" {
+			if diagnostics == nil {
+				diagnostics = []venom.Diagnostic{}
+			}
+			btes, err := json.MarshalIndent(diagnostics, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(btes))
+		} else {
+			for _, d := range diagnostics {
+				fmt.Fprintln(cmd.OutOrStdout(), d.String())
+			}
+		}
+		if len(diagnostics) > 0 {
+			return fmt.Errorf("%d problem(s) found", len(diagnostics))
+		}
+		return nil
+	},
+}
@@ -0,0 +1,116 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rockbears/yaml"
+)
+
+// DryRunStep is a step as its executor would have received it, in dry-run mode
+type DryRunStep struct {
+	Name     string   `json:"name"`
+	Executor string   `json:"executor"`
+	Step     TestStep `json:"step"`
+	// the template expressions of the step which have not been interpolated
+	Unresolved []string `json:"unresolved,omitempty"`
+	// the steps of a user executor
+	Steps []DryRunStep `json:"steps,omitempty"`
+}
+
+var templateExpressionRegex = regexp.MustCompile(`{{.*?}}`)
+
+// dryRunTestStep records the interpolated step instead of running its executor. The steps of a user executor are expanded,
+// and the variables assigned from the results of the step, which are unknown, are placeholders.
+func (v *Venom) dryRunTestStep(ctx context.Context, e ExecutorRunner, tc *TestCase, tsResult *TestStepResult, tsIn *TestStepResult, stepNumber int, rangedIndex int, rawStep json.RawMessage, step TestStep) {
+	// the secrets are hidden from the step printed and written in the reports
+	recorded := step
+	if btes, err := json.Marshal(step); err == nil {
+		var redacted TestStep
+		if err := json.Unmarshal([]byte(HideSensitive(ctx, string(btes))), &redacted); err == nil {
+			recorded = redacted
+		}
+	}
+	tsResult.DryRun = &DryRunStep{
+		Name:       tsResult.Name,
+		Executor:   e.Name(),
+		Step:       recorded,
+		Unresolved: unresolvedExpressions(step),
+	}
+	tsResult.AssertionsApplied.OK = true
+	tsResult.ComputedVars = H{}
+
+	if e.Type() == "user" {
+		result, err := v.RunUserExecutor(ctx, e, tc, tsResult, step)
+		if err != nil {
+			tsResult.appendFailure(*newFailure(ctx, *tc, stepNumber, rangedIndex, "", err))
+		} else {
+			tsResult.ComputedVars.AddAll(H(GetExecutorResult(result)))
+		}
+	}
+
+	var assignments AssignStep
+	if err := yaml.Unmarshal(rawStep, &assignments); err == nil {
+		for _, a := range assignments.Assignments {
+			if _, ok := tsResult.ComputedVars[a.From]; !ok {
+				tsResult.ComputedVars.Add(a.From, fmt.Sprintf("<%s of step #%d of %s>", a.From, stepNumber, tc.Name))
+			}
+		}
+	}
+
+	// the steps of a user executor are recorded in the step calling it
+	if tsIn != nil && tsIn.DryRun != nil {
+		tsIn.DryRun.Steps = append(tsIn.DryRun.Steps, *tsResult.DryRun)
+	}
+}
+
+// unresolvedExpressions returns the template expressions remaining in the step,
+// except in the attributes which are evaluated on the results of the step
+func unresolvedExpressions(step TestStep) []string {
+	dump, err := DumpStringPreserveCase(step)
+	if err != nil {
+		return nil
+	}
+	var expressions []string
+	for k, value := range dump {
+		switch strings.Split(k, ".")[0] {
+		case "assertions", "info", "vars", "extracts", "until", "retry_if", "skip":
+			continue
+		}
+		expressions = appendUniq(expressions, templateExpressionRegex.FindAllString(value, -1)...)
+	}
+	sort.Strings(expressions)
+	return expressions
+}
+
+// printDryRunSteps prints the steps of the testcase as their executors would have received them
+func (v *Venom) printDryRunSteps(tc *TestCase) {
+	for _, tsResult := range tc.TestStepResults {
+		if tsResult.DryRun != nil {
+			v.printDryRunStep(*tsResult.DryRun, " \t\t")
+		}
+	}
+}
+
+func (v *Venom) printDryRunStep(s DryRunStep, indent string) {
+	name := s.Name
+	if s.Executor != "" && s.Executor != name {
+		name += " " + Gray("("+s.Executor+")")
+	}
+	v.Println("%s• %s", indent, name)
+	if content, err := yaml.Marshal(s.Step); err == nil {
+		for _, line := range strings.Split(strings.TrimSuffix(string(content), "\n"), "\n") {
+			v.Println("%s  %s", indent, line)
+		}
+	}
+	for _, expression := range s.Unresolved {
+		v.Println("%s  %s", indent, Yellow("unresolved: "+expression))
+	}
+	for _, child := range s.Steps {
+		v.printDryRunStep(child, indent+"  ")
+	}
+}
@@ -0,0 +1,88 @@
+package venom
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessDryRun(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"suite.yml": `name: suite
+vars:
+  url: http://localhost
+testcases:
+- name: create
+  steps:
+  - type: echo
+    value: '{{.url}}/items'
+    vars:
+      id:
+        from: result.value
+  - type: echo
+    range: [a, b]
+    value: '{{.create.id}}/{{.value}}'
+  - type: echo
+    skip:
+    - url ShouldBeEmpty
+    value: skipped
+  - type: echo
+    value: '{{.create.unknown}}'
+    assertions:
+    - result.value ShouldEqual {{.url}}
+- name: user
+  steps:
+  - type: user
+    path: items
+    vars:
+      body:
+        from: result.body
+`,
+		"lib/user.yml": `executor: user
+input:
+  path: {}
+steps:
+- type: echo
+  value: '{{.url}}/{{.input.path}}'
+  vars:
+    value:
+      from: result.value
+output:
+  body: '{{.value}}'
+`,
+	})
+	called := false
+	v := newTestVenom(t, map[string]Executor{"echo": funcExecutor(func(ctx context.Context, step TestStep) (interface{}, error) {
+		called = true
+		return nil, nil
+	})})
+	v.LibDir = filepath.Join(dir, "lib")
+	v.DryRun = true
+	suite := filepath.Join(dir, "suite.yml")
+	require.NoError(t, v.Parse(context.Background(), []string{suite}))
+	require.NoError(t, v.Process(context.Background(), []string{suite}))
+
+	require.False(t, called)
+	require.Equal(t, StatusPass, v.Tests.Status)
+	ts := v.Tests.TestSuites[0]
+
+	create := ts.TestCases[0].TestStepResults
+	require.Len(t, create, 5)
+	require.Equal(t, "echo", create[0].DryRun.Executor)
+	require.Equal(t, "http://localhost/items", create[0].DryRun.Step["value"])
+	require.Equal(t, "<result.value of step #1 of create>/a", create[1].DryRun.Step["value"])
+	require.Equal(t, "<result.value of step #1 of create>/b", create[2].DryRun.Step["value"])
+	require.Equal(t, StatusSkip, create[3].Status)
+	require.Nil(t, create[3].DryRun)
+	require.Equal(t, []string{"{{.create.unknown}}"}, create[4].DryRun.Unresolved)
+
+	user := ts.TestCases[1].TestStepResults
+	require.Len(t, user, 1)
+	require.Equal(t, "user", user[0].DryRun.Executor)
+	require.Equal(t, "items", user[0].DryRun.Step["path"])
+	require.Len(t, user[0].DryRun.Steps, 1)
+	require.Equal(t, "http://localhost/items", user[0].DryRun.Steps[0].Step["value"])
+	require.Equal(t, "<result.value of step #1 of user>", user[0].ComputedVars["result.body"])
+}
@@ -0,0 +1,59 @@
+package venom
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// Duration is a duration which can be written as a number of seconds, or as a Go duration string such as "500ms" or "1m30s"
+type Duration time.Duration
+
+// UnmarshalJSON accepts a number of seconds or a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	duration, err := parseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// MarshalJSON writes the duration as a duration string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// parseDuration parses a number of seconds or a duration string
+func parseDuration(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case time.Duration:
+		return v, nil
+	case Duration:
+		return time.Duration(v), nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		if d, err := time.ParseDuration(v); err == nil {
+			return d, nil
+		}
+	}
+	seconds, err := cast.ToFloat64E(value)
+	if err != nil {
+		return 0, fmt.Errorf("%v is neither a number of seconds nor a duration", value)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,37 @@
+package venom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurationUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{data: `2`, want: 2 * time.Second},
+		{data: `0.5`, want: 500 * time.Millisecond},
+		{data: `"3"`, want: 3 * time.Second},
+		{data: `"250ms"`, want: 250 * time.Millisecond},
+		{data: `"1m30s"`, want: 90 * time.Second},
+		{data: `""`, want: 0},
+		{data: `"soon"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			var d Duration
+			err := json.Unmarshal([]byte(tt.data), &d)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, time.Duration(d))
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// EventsVersion is the version of the schema of the events, increased on each breaking change of Event
+const EventsVersion = 1
+
+// EventType is the type of an event of the run
+type EventType string
+
+const (
+	EventSuiteStart    EventType = "suite_start"
+	EventTestCaseStart EventType = "testcase_start"
+	EventStepStart     EventType = "step_start"
+	EventStepEnd       EventType = "step_end"
+	EventTestCaseEnd   EventType = "testcase_end"
+	EventSuiteEnd      EventType = "suite_end"
+	EventRunEnd        EventType = "run_end"
+)
+
+// Event is written as a line of json into Venom.Events, while the testsuites are run.
+// The names of the steps and the errors are redacted from the secrets.
+type Event struct {
+	Version    int       `json:"version"`
+	Type       EventType `json:"type"`
+	Time       time.Time `json:"time"`
+	TestSuite  string    `json:"testsuite,omitempty"`
+	Filepath   string    `json:"filepath,omitempty"`
+	TestCase   string    `json:"testcase,omitempty"`
+	Step       string    `json:"step,omitempty"`
+	StepNumber int       `json:"stepNumber,omitempty"`
+	// Status and Duration are set on the end events
+	Status   Status   `json:"status,omitempty"`
+	Duration float64  `json:"duration,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+	// the counters of the testsuites are set on the run_end event
+	NbTestsuitesFail int `json:"nbTestsuitesFail,omitempty"`
+	NbTestsuitesPass int `json:"nbTestsuitesPass,omitempty"`
+	NbTestsuitesSkip int `json:"nbTestsuitesSkip,omitempty"`
+}
+
+// eventWriter writes the events of a run as newline-delimited json, it is notified as the other hooks
+type eventWriter struct {
+	w io.Writer
+	// mutex is shared between the copies of venom running the testsuites in parallel
+	mutex *sync.Mutex
+}
+
+func (w *eventWriter) write(ctx context.Context, e Event) {
+	e.Version = EventsVersion
+	e.Time = time.Now()
+	btes, err := json.Marshal(e)
+	if err != nil {
+		Error(ctx, "unable to marshal event %s: %v", e.Type, err)
+		return
+	}
+
+	// the events of the testsuites run in parallel must not be interleaved
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if _, err := w.w.Write(append(btes, '\n')); err != nil {
+		Error(ctx, "unable to write event %s: %v", e.Type, err)
+	}
+}
+
+func (w *eventWriter) OnSuiteStart(ctx context.Context, ts *TestSuite) {
+	w.write(ctx, Event{Type: EventSuiteStart, TestSuite: ts.Name, Filepath: ts.Filepath})
+}
+
+func (w *eventWriter) OnSuiteEnd(ctx context.Context, ts *TestSuite) {
+	w.write(ctx, Event{
+		Type:      EventSuiteEnd,
+		TestSuite: ts.Name,
+		Filepath:  ts.Filepath,
+		Status:    ts.Status,
+		Duration:  time.Since(ts.Start).Seconds(),
+	})
+}
+
+func (w *eventWriter) OnTestCaseStart(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	w.write(ctx, Event{Type: EventTestCaseStart, TestSuite: ts.Name, Filepath: ts.Filepath, TestCase: tc.Name})
+}
+
+func (w *eventWriter) OnTestCaseEnd(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	w.write(ctx, Event{
+		Type:      EventTestCaseEnd,
+		TestSuite: ts.Name,
+		Filepath:  ts.Filepath,
+		TestCase:  tc.Name,
+		Status:    tc.Status,
+		Duration:  tc.Duration,
+	})
+}
+
+func (w *eventWriter) OnStepStart(ctx context.Context, tc *TestCase, result *TestStepResult) {
+	w.write(ctx, stepEvent(ctx, EventStepStart, tc, result))
+}
+
+func (w *eventWriter) OnStepEnd(ctx context.Context, tc *TestCase, result *TestStepResult) {
+	e := stepEvent(ctx, EventStepEnd, tc, result)
+	e.Status = result.Status
+	e.Duration = result.Duration
+	for _, f := range result.Errors {
+		e.Errors = append(e.Errors, HideSensitive(ctx, f.Value))
+	}
+	w.write(ctx, e)
+}
+
+func (w *eventWriter) onRunEnd(ctx context.Context, tests *Tests) {
+	w.write(ctx, Event{
+		Type:             EventRunEnd,
+		Status:           tests.Status,
+		Duration:         tests.Duration,
+		NbTestsuitesFail: tests.NbTestsuitesFail,
+		NbTestsuitesPass: tests.NbTestsuitesPass,
+		NbTestsuitesSkip: tests.NbTestsuitesSkip,
+	})
+}
+
+func stepEvent(ctx context.Context, t EventType, tc *TestCase, result *TestStepResult) Event {
+	return Event{
+		Type:       t,
+		TestSuite:  StringVarFromCtx(ctx, "venom.testsuite"),
+		Filepath:   StringVarFromCtx(ctx, "venom.testsuite.filepath"),
+		TestCase:   tc.Name,
+		Step:       HideSensitive(ctx, result.Name),
+		StepNumber: result.Number,
+	}
+}
@@ -0,0 +1,75 @@
+package venom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessEvents(t *testing.T) {
+	var buf bytes.Buffer
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.Events = &buf
+	runTestSuites(t, v, `name: suite
+vars:
+  password: s3cret
+secrets:
+- password
+testcases:
+- name: ok
+  steps:
+  - type: echo
+    value: foo
+- name: ko
+  steps:
+  - type: echo
+    value: '{{.password}}'
+    assertions:
+    - result.value ShouldEqual bar
+`)
+
+	require.NotContains(t, buf.String(), "s3cret")
+	var events []Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		require.Equal(t, EventsVersion, e.Version)
+		events = append(events, e)
+	}
+	require.NoError(t, scanner.Err())
+
+	var types []EventType
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	require.Equal(t, []EventType{
+		EventSuiteStart,
+		EventTestCaseStart, EventStepStart, EventStepEnd, EventTestCaseEnd,
+		EventTestCaseStart, EventStepStart, EventStepEnd, EventTestCaseEnd,
+		EventSuiteEnd,
+		EventRunEnd,
+	}, types)
+
+	require.Equal(t, "suite", events[0].TestSuite)
+	require.Equal(t, "ok", events[2].TestCase)
+	require.Equal(t, StatusPass, events[3].Status)
+	require.Equal(t, 1, events[3].StepNumber)
+
+	failed := events[7]
+	require.Equal(t, "suite", failed.TestSuite)
+	require.Equal(t, "ko", failed.TestCase)
+	require.Equal(t, StatusFail, failed.Status)
+	require.Len(t, failed.Errors, 1)
+	require.NotContains(t, failed.Errors[0], "s3cret")
+	require.Contains(t, failed.Errors[0], "__hidden__")
+	require.Equal(t, StatusFail, events[8].Status)
+	require.Equal(t, StatusFail, events[9].Status)
+
+	runEnd := events[10]
+	require.Equal(t, StatusFail, runEnd.Status)
+	require.Equal(t, 1, runEnd.NbTestsuitesFail)
+}
@@ -0,0 +1,98 @@
+package venom
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/gosimple/slug"
+)
+
+// filterTestCase skips the i-th testcase of the testsuite if it is not one of the testcases matching the RunFilter regexp when matched is not nil,
+// if it is not one of the testcases matching the Tags and ExcludeTags expressions when tagged is not nil,
+// or if it is not one of the failed testcases to rerun when failed is not nil.
+// It returns true if the testcase is selected.
+func (v *Venom) filterTestCase(ts *TestSuite, i int, failed, matched, tagged map[int]struct{}) bool {
+	tc := &ts.TestCases[i]
+	if failed != nil {
+		if _, ok := failed[i]; !ok {
+			tc.Skipped = append(tc.Skipped, Skipped{Value: fmt.Sprintf("===== %s/%s did not fail in %s =====", ts.Name, tc.Name, v.RerunFailed)})
+			return false
+		}
+	}
+
+	if _, ok := matched[i]; matched != nil && !ok {
+		name := tc.originalName
+		if tc.rangeItem != nil {
+			name += "[" + tc.rangeItem.label + "]"
+		}
+		tc.Skipped = append(tc.Skipped, Skipped{Value: fmt.Sprintf("===== %s/%s does not match %q =====", ts.Name, name, v.RunFilter)})
+		return false
+	}
+
+	if _, ok := tagged[i]; tagged != nil && !ok {
+		tags := ts.testCaseTags(*tc)
+		if v.tagsFilter != nil && !v.tagsFilter.match(tags) {
+			tc.Skipped = append(tc.Skipped, Skipped{Value: fmt.Sprintf("===== tags %v do not match %q =====", tags, v.Tags)})
+		} else {
+			tc.Skipped = append(tc.Skipped, Skipped{Value: fmt.Sprintf("===== tags %v excluded by %q =====", tags, v.ExcludeTags)})
+		}
+		return false
+	}
+	tc.rerun = failed != nil
+	return true
+}
+
+// tagsFilterTestCases returns the testcases of the testsuite whose tags match the Tags and ExcludeTags expressions
+// and the testcases they depend on, or nil if there is no tags expression
+func (v *Venom) tagsFilterTestCases(ts *TestSuite) map[int]struct{} {
+	if v.tagsFilter == nil && v.excludeTagsFilter == nil {
+		return nil
+	}
+	tagged := map[int]struct{}{}
+	for i, tc := range ts.TestCases {
+		tags := ts.testCaseTags(tc)
+		if v.tagsFilter != nil && !v.tagsFilter.match(tags) {
+			continue
+		}
+		if v.excludeTagsFilter != nil && v.excludeTagsFilter.match(tags) {
+			continue
+		}
+		ts.selectWithDependencies(tagged, i)
+	}
+	return tagged
+}
+
+// runFilterTestCases returns the testcases of the testsuite matching the RunFilter regexp and the testcases they depend on,
+// or nil if there is no RunFilter
+func (v *Venom) runFilterTestCases(ts *TestSuite) map[int]struct{} {
+	if v.runFilter == nil {
+		return nil
+	}
+	matched := map[int]struct{}{}
+	for i, tc := range ts.TestCases {
+		if matchRunFilter(v.runFilter, ts.Name, tc.originalName, tc.rangeItem) {
+			ts.selectWithDependencies(matched, i)
+		}
+	}
+	return matched
+}
+
+// matchRunFilter returns true if the regexp matches "testsuite/testcase",
+// using either the original names or their slug form.
+// The name of a ranged testcase is followed by the label of its item, like "testcase[item]".
+func matchRunFilter(re *regexp.Regexp, testSuiteName, testCaseName string, rangeItem *testCaseRangeItem) bool {
+	tcNames := []string{testCaseName, slug.Make(testCaseName)}
+	if rangeItem != nil {
+		for i := range tcNames {
+			tcNames[i] += "[" + rangeItem.label + "]"
+		}
+	}
+	for _, tsName := range []string{testSuiteName, slug.Make(testSuiteName)} {
+		for _, tcName := range tcNames {
+			if re.MatchString(tsName + "/" + tcName) {
+				return true
+			}
+		}
+	}
+	return false
+}
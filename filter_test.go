@@ -0,0 +1,107 @@
+package venom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTags(t *testing.T) {
+	suite := `name: suite
+tags: [payments]
+setup:
+- type: echo
+  value: foo
+testcases:
+- name: smoke
+  tags: [smoke]
+  steps:
+  - type: echo
+    value: foo
+- name: slow
+  tags: [smoke, slow]
+  steps:
+  - type: echo
+    value: foo
+- name: untagged
+  steps:
+  - type: echo
+    value: foo
+`
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.Tags = "smoke && payments"
+	v.ExcludeTags = "slow"
+	runTestSuites(t, v, suite)
+
+	ts := v.Tests.TestSuites[0]
+	require.Equal(t, StatusPass, ts.Status)
+	require.Equal(t, StatusPass, ts.Setup.Status)
+	require.Equal(t, StatusPass, ts.TestCases[0].Status)
+	require.Equal(t, StatusSkip, ts.TestCases[1].Status)
+	require.Equal(t, `===== tags [payments smoke slow] excluded by "slow" =====`, ts.TestCases[1].Skipped[0].Value)
+	require.Equal(t, StatusSkip, ts.TestCases[2].Status)
+	require.Equal(t, `===== tags [payments] do not match "smoke && payments" =====`, ts.TestCases[2].Skipped[0].Value)
+
+	data, err := outputXMLFormat(v.Tests, 0)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `<testsuite name="suite" package=`)
+	require.Contains(t, string(data), `<properties>
+      <property name="tag" value="payments"></property>
+    </properties>`)
+	require.Contains(t, string(data), `<property name="tag" value="smoke"></property>`)
+
+	v = newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.Tags = "unknown"
+	runTestSuites(t, v, suite)
+
+	ts = v.Tests.TestSuites[0]
+	require.Equal(t, StatusSkip, ts.Status)
+	require.Equal(t, StatusSkip, ts.Setup.Status)
+}
+
+func TestProcessRunFilter(t *testing.T) {
+	suite := `name: Auth suite
+testcases:
+- name: login ok
+  steps:
+  - type: echo
+    value: foo
+- name: login ko
+  steps:
+  - type: only-used-here
+    value: foo
+- name: logout
+  steps:
+  - type: echo
+    value: foo
+`
+	tests := []struct {
+		filter string
+		want   []Status
+	}{
+		{filter: "login.ok", want: []Status{StatusPass, StatusSkip, StatusSkip}},
+		{filter: "Auth suite/login ok", want: []Status{StatusPass, StatusSkip, StatusSkip}},
+		{filter: "^auth-suite/(login-ok|logout)$", want: []Status{StatusPass, StatusSkip, StatusPass}},
+		{filter: "other-suite/", want: []Status{StatusSkip, StatusSkip, StatusSkip}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+			v.RunFilter = tt.filter
+			runTestSuites(t, v, suite)
+
+			for i, tc := range v.Tests.TestSuites[0].TestCases {
+				require.Equal(t, tt.want[i], tc.Status, tc.Name)
+			}
+		})
+	}
+
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	dir := writeTestSuites(t, suite)
+	require.ErrorContains(t, v.Parse(context.Background(), []string{dir}), `executor "only-used-here" not found`)
+
+	v = newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.RunFilter = "login ("
+	require.ErrorContains(t, v.Parse(context.Background(), []string{dir}), `invalid run filter "login ("`)
+}
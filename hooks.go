@@ -0,0 +1,59 @@
+package venom
+
+import "context"
+
+// Hooks are notified of the progress of a run, they are registered with RegisterHooks.
+// The hooks of the testsuites and testcases run in parallel are called concurrently.
+// The steps of the user executors are not notified, they are part of the step calling the executor.
+type Hooks interface {
+	OnSuiteStart(ctx context.Context, ts *TestSuite)
+	// OnSuiteEnd is called once the status of the testsuite is computed
+	OnSuiteEnd(ctx context.Context, ts *TestSuite)
+	// OnTestCaseStart is called for each testcase, the setup and the teardown of the testsuite, even if they are skipped
+	OnTestCaseStart(ctx context.Context, ts *TestSuite, tc *TestCase)
+	// OnTestCaseEnd is called once the status of the testcase is computed
+	OnTestCaseEnd(ctx context.Context, ts *TestSuite, tc *TestCase)
+	// OnStepStart is called before running a step, the name of its testsuite is the venom.testsuite variable of tc
+	OnStepStart(ctx context.Context, tc *TestCase, result *TestStepResult)
+	// OnStepEnd is called once the status and the errors of the step are known
+	OnStepEnd(ctx context.Context, tc *TestCase, result *TestStepResult)
+}
+
+// NopHooks does nothing, it can be embedded to implement only some of the hooks
+type NopHooks struct{}
+
+func (NopHooks) OnSuiteStart(context.Context, *TestSuite)                {}
+func (NopHooks) OnSuiteEnd(context.Context, *TestSuite)                  {}
+func (NopHooks) OnTestCaseStart(context.Context, *TestSuite, *TestCase)  {}
+func (NopHooks) OnTestCaseEnd(context.Context, *TestSuite, *TestCase)    {}
+func (NopHooks) OnStepStart(context.Context, *TestCase, *TestStepResult) {}
+func (NopHooks) OnStepEnd(context.Context, *TestCase, *TestStepResult)   {}
+
+// RegisterHooks registers hooks notified of the progress of the runs
+func (v *Venom) RegisterHooks(h Hooks) {
+	v.hooks = append(v.hooks, h)
+}
+
+// notify calls f with the writer of the events, if any, and with each of the registered hooks
+func (v *Venom) notify(f func(h Hooks)) {
+	if v.events != nil {
+		f(v.events)
+	}
+	for _, h := range v.hooks {
+		f(h)
+	}
+}
+
+// Run parses and runs the testsuites of paths, and returns their results.
+// Unlike the venom run command, it never exits the process and prints nothing unless PrintFunc or Output is set:
+// the progress of the run can be followed with hooks, and the reports are written by OutputResult.
+func (v *Venom) Run(ctx context.Context, paths []string) (*Tests, error) {
+	v.Tests = Tests{TestSuites: []TestSuite{}}
+	if err := v.Parse(ctx, paths); err != nil {
+		return nil, err
+	}
+	if err := v.Process(ctx, paths); err != nil {
+		return nil, err
+	}
+	return &v.Tests, nil
+}
@@ -0,0 +1,164 @@
+package venom
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordHooks records the calls of the hooks
+type recordHooks struct {
+	calls []string
+}
+
+func (h *recordHooks) OnSuiteStart(ctx context.Context, ts *TestSuite) {
+	h.calls = append(h.calls, "suite start "+ts.Name)
+}
+
+func (h *recordHooks) OnSuiteEnd(ctx context.Context, ts *TestSuite) {
+	h.calls = append(h.calls, fmt.Sprintf("suite end %s %s", ts.Name, ts.Status))
+}
+
+func (h *recordHooks) OnTestCaseStart(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	h.calls = append(h.calls, "testcase start "+tc.Name)
+}
+
+func (h *recordHooks) OnTestCaseEnd(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	h.calls = append(h.calls, fmt.Sprintf("testcase end %s %s", tc.Name, tc.Status))
+}
+
+func (h *recordHooks) OnStepStart(ctx context.Context, tc *TestCase, result *TestStepResult) {
+	h.calls = append(h.calls, fmt.Sprintf("step start %s #%d", tc.Name, result.Number))
+}
+
+func (h *recordHooks) OnStepEnd(ctx context.Context, tc *TestCase, result *TestStepResult) {
+	h.calls = append(h.calls, fmt.Sprintf("step end %s #%d %s %d", tc.Name, result.Number, result.Status, len(result.Errors)))
+}
+
+// testCaseEndHooks implements only OnTestCaseEnd
+type testCaseEndHooks struct {
+	NopHooks
+	statuses map[string]Status
+}
+
+func (h *testCaseEndHooks) OnTestCaseEnd(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	h.statuses[tc.Name] = tc.Status
+}
+
+func TestRun(t *testing.T) {
+	dir := writeTestSuites(t, `name: suite
+testcases:
+- name: ok
+  steps:
+  - type: echo
+    value: foo
+  - type: echo
+    value: bar
+- name: ko
+  steps:
+  - type: echo
+    value: foo
+    assertions:
+    - result.value ShouldEqual bar
+- name: skipped
+  skip:
+  - foo ShouldEqual bar
+  steps:
+  - type: echo
+`)
+
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	var printed bytes.Buffer
+	v.PrintFunc = func(format string, a ...interface{}) (int, error) {
+		return fmt.Fprintf(&printed, format, a...)
+	}
+	record := &recordHooks{}
+	v.RegisterHooks(record)
+	statuses := &testCaseEndHooks{statuses: map[string]Status{}}
+	v.RegisterHooks(statuses)
+
+	tests, err := v.Run(context.Background(), []string{dir})
+	require.NoError(t, err)
+	require.Equal(t, StatusFail, tests.Status)
+	require.Len(t, tests.TestSuites, 1)
+
+	require.Equal(t, []string{
+		"suite start suite",
+		"testcase start ok",
+		"step start ok #1",
+		"step end ok #1 PASS 0",
+		"step start ok #2",
+		"step end ok #2 PASS 0",
+		"testcase end ok PASS",
+		"testcase start ko",
+		"step start ko #1",
+		"step end ko #1 FAIL 1",
+		"testcase end ko FAIL",
+		"testcase start skipped",
+		"testcase end skipped SKIP",
+		"suite end suite FAIL",
+	}, record.calls)
+	require.Equal(t, map[string]Status{"ok": StatusPass, "ko": StatusFail, "skipped": StatusSkip}, statuses.statuses)
+
+	// the PrintFunc of the caller prints the run and the reports
+	require.Contains(t, printed.String(), "• suite")
+	v.OutputDir = t.TempDir()
+	require.NoError(t, v.OutputResult())
+	require.Contains(t, printed.String(), "Writing file")
+
+	// without PrintFunc, the output is printed only into Output, the results of the previous run are not kept
+	printed.Reset()
+	v.PrintFunc = nil
+	var buf bytes.Buffer
+	v.Output = &buf
+	tests, err = v.Run(context.Background(), []string{dir})
+	require.NoError(t, err)
+	require.Len(t, tests.TestSuites, 1)
+	require.Contains(t, buf.String(), "• suite")
+	require.NoError(t, v.OutputResult())
+	require.Contains(t, buf.String(), "Writing file")
+	require.Empty(t, printed.String())
+
+	_, err = v.Run(context.Background(), []string{t.TempDir()})
+	require.Error(t, err)
+}
+
+func TestRunTwiceUserExecutor(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"suite.yml": `name: suite
+testcases:
+- name: hello
+  steps:
+  - type: hello
+    name: world
+    assertions:
+    - result.greeting ShouldEqual "hello world"
+`,
+		"lib/hello.yml": `executor: hello
+input:
+  name: {}
+steps:
+- type: echo
+  value: 'hello {{.input.name}}'
+  vars:
+    value:
+      from: result.value
+output:
+  greeting: '{{.value}}'
+`,
+	})
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.LibDir = filepath.Join(dir, "lib")
+	suite := filepath.Join(dir, "suite.yml")
+
+	// the user executors registered by the first run are registered again from the same files
+	for i := 0; i < 2; i++ {
+		tests, err := v.Run(context.Background(), []string{suite})
+		require.NoError(t, err, "run #%d", i+1)
+		require.Equal(t, StatusPass, tests.Status, "run #%d", i+1)
+	}
+}
@@ -0,0 +1,203 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ListedTestSuite is a testsuite as read from its file, without running it
+type ListedTestSuite struct {
+	Name      string           `json:"name"`
+	Filepath  string           `json:"filepath"`
+	Tags      []string         `json:"tags,omitempty"`
+	Executors []string         `json:"executors,omitempty"`
+	Setup     *ListedTestCase  `json:"setup,omitempty"`
+	TestCases []ListedTestCase `json:"testcases"`
+	Teardown  *ListedTestCase  `json:"teardown,omitempty"`
+}
+
+// ListedTestCase is a testcase as read from its file, without running it
+type ListedTestCase struct {
+	Name string `json:"name"`
+	// the tags of the testcase, including the ones of its testsuite
+	Tags []string `json:"tags,omitempty"`
+	// the number of steps, including the included and the finally steps
+	Steps     int         `json:"steps"`
+	Executors []string    `json:"executors,omitempty"`
+	Skip      []string    `json:"skip,omitempty"`
+	Range     interface{} `json:"range,omitempty"`
+	DependsOn []string    `json:"dependsOn,omitempty"`
+	Position  *Position   `json:"position,omitempty"`
+}
+
+// ListedExecutor is an executor which can be used by the steps
+type ListedExecutor struct {
+	Name string `json:"name"`
+	// builtin, user or plugin
+	Type string `json:"type"`
+	// the file of a user executor or of a plugin
+	File string `json:"file,omitempty"`
+	// the inputs of a user executor, with their default values
+	Inputs map[string]interface{} `json:"inputs,omitempty"`
+	// the outputs of a user executor
+	Outputs []string `json:"outputs,omitempty"`
+}
+
+// List reads the testsuites of the paths without running them, and returns their testcases, the executors they use, their tags and skip conditions.
+// The filters of the run are not applied.
+func (v *Venom) List(ctx context.Context, paths []string) ([]ListedTestSuite, error) {
+	filesPath, err := getFilesPath(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := DumpStringPreserveCase(v.variables)
+	if err != nil {
+		return nil, err
+	}
+	// the user executors given as paths are not testsuites
+	executorFiles := map[string]bool{}
+	for _, f := range v.getUserExecutorFilesPath(ctx, vars) {
+		executorFiles[f] = true
+	}
+	var testSuitesPath []string
+	for _, f := range filesPath {
+		if abs, err := filepath.Abs(f); err == nil && executorFiles[abs] {
+			continue
+		}
+		testSuitesPath = append(testSuitesPath, f)
+	}
+
+	n := len(v.Tests.TestSuites)
+	if err := v.readFiles(ctx, testSuitesPath); err != nil {
+		return nil, err
+	}
+	testSuites := v.Tests.TestSuites[n:]
+	v.Tests.TestSuites = v.Tests.TestSuites[:n]
+
+	listed := make([]ListedTestSuite, 0, len(testSuites))
+	for _, ts := range testSuites {
+		lts := ListedTestSuite{
+			Name:      ts.Name,
+			Filepath:  ts.Filepath,
+			Tags:      ts.Tags,
+			TestCases: []ListedTestCase{},
+		}
+		if ts.Setup != nil {
+			setup := ts.listTestCase(*ts.Setup)
+			lts.Setup = &setup
+			lts.Executors = appendUniq(lts.Executors, setup.Executors...)
+		}
+		for _, tc := range ts.TestCases {
+			ltc := ts.listTestCase(tc)
+			lts.TestCases = append(lts.TestCases, ltc)
+			lts.Executors = appendUniq(lts.Executors, ltc.Executors...)
+		}
+		if ts.Teardown != nil {
+			teardown := ts.listTestCase(*ts.Teardown)
+			lts.Teardown = &teardown
+			lts.Executors = appendUniq(lts.Executors, teardown.Executors...)
+		}
+		listed = append(listed, lts)
+	}
+	return listed, nil
+}
+
+// listTestCase returns the testcase as listed, the executors being the types of its steps in their order of appearance
+func (ts TestSuite) listTestCase(tc TestCase) ListedTestCase {
+	steps := tc.allRawTestSteps()
+	ltc := ListedTestCase{
+		Name:      tc.Name,
+		Tags:      ts.testCaseTags(tc),
+		Steps:     len(steps),
+		Skip:      tc.Skip,
+		Range:     tc.Range,
+		DependsOn: tc.DependsOn,
+		Position:  tc.Position,
+	}
+	for _, rawStep := range steps {
+		var step TestStep
+		if err := json.Unmarshal(rawStep, &step); err != nil {
+			continue
+		}
+		if name := step.executorName(); name != "" {
+			ltc.Executors = appendUniq(ltc.Executors, name)
+		}
+	}
+	return ltc
+}
+
+func appendUniq(list []string, values ...string) []string {
+	for _, value := range values {
+		if !slices.Contains(list, value) {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// ListExecutors returns the builtin executors, the user executors of the lib directories with their inputs and outputs,
+// and the plugins of the lib directories, sorted by name.
+func (v *Venom) ListExecutors(ctx context.Context) ([]ListedExecutor, error) {
+	var listed []ListedExecutor
+	for name := range v.executorsBuiltin {
+		listed = append(listed, ListedExecutor{Name: name, Type: "builtin"})
+	}
+
+	vars, err := DumpStringPreserveCase(v.variables)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range v.getUserExecutorFilesPath(ctx, vars) {
+		ux, err := readUserExecutor(f)
+		if err != nil {
+			return nil, err
+		}
+		var inputs struct {
+			Input map[string]interface{} `yaml:"input"`
+		}
+		// the default values of the inputs can be template expressions
+		if err := yaml.Unmarshal(quoteTemplateExpressions(ux.RawInputs), &inputs); err != nil {
+			Warn(ctx, "unable to read the input of the user executor %q: %v", ux.Executor, err)
+		}
+		var outputs struct {
+			Output map[string]interface{} `yaml:"output"`
+		}
+		if err := yaml.Unmarshal(quoteTemplateExpressions([]byte(readPartialYML(ux.Raw, "output"))), &outputs); err != nil {
+			Warn(ctx, "unable to read the output of the user executor %q: %v", ux.Executor, err)
+		}
+		// an input without default value is written as {}
+		for k, value := range inputs.Input {
+			if m, ok := value.(map[string]interface{}); ok && len(m) == 0 {
+				inputs.Input[k] = nil
+			}
+		}
+		le := ListedExecutor{Name: ux.Executor, Type: "user", File: f, Inputs: inputs.Input}
+		for k := range outputs.Output {
+			le.Outputs = append(le.Outputs, k)
+		}
+		sort.Strings(le.Outputs)
+		listed = append(listed, le)
+	}
+
+	for _, dir := range v.libDirs(vars) {
+		plugins, err := filepath.Glob(filepath.Join(dir, "*.so"))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range plugins {
+			listed = append(listed, ListedExecutor{Name: strings.TrimSuffix(filepath.Base(p), ".so"), Type: "plugin", File: p})
+		}
+	}
+
+	sort.SliceStable(listed, func(i, j int) bool {
+		return listed[i].Name < listed[j].Name
+	})
+	return listed, nil
+}
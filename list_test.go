@@ -0,0 +1,90 @@
+package venom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"suite.yml": `name: suite
+tags: [api]
+setup:
+- script: echo setup
+testcases:
+- name: first
+  tags: [slow]
+  skip:
+  - foo ShouldBeEmpty
+  steps:
+  - type: value
+    value: a
+  - include: steps.yml
+  - type: user
+- name: second
+  range: [1, 2]
+  steps:
+  - assertions:
+    - foo ShouldNotBeEmpty
+  finally:
+  - script: echo done
+`,
+		"steps.yml": `steps:
+- type: echo
+- type: value
+  value: b
+`,
+		"lib/user.yml": `executor: user
+input:
+  url: http://localhost
+  id: {}
+steps:
+- type: value
+  value: '{{.input.url}}/{{.input.id}}'
+output:
+  body: '{{.result.value}}'
+  url: '{{.input.url}}'
+`,
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lib", "custom.so"), nil, 0o644))
+	v := newTestVenom(t, map[string]Executor{"value": valueExecutor{}, "echo": echoExecutor})
+	v.LibDir = filepath.Join(dir, "lib")
+
+	testSuites, err := v.List(context.Background(), []string{filepath.Join(dir, "suite.yml"), filepath.Join(dir, "lib")})
+	require.NoError(t, err)
+	require.Len(t, testSuites, 1)
+	ts := testSuites[0]
+	require.Equal(t, "suite", ts.Name)
+	require.Equal(t, []string{"api"}, ts.Tags)
+	require.Equal(t, []string{"exec", "value", "echo", "user"}, ts.Executors)
+	require.NotNil(t, ts.Setup)
+	require.Equal(t, 1, ts.Setup.Steps)
+	require.Nil(t, ts.Teardown)
+
+	require.Len(t, ts.TestCases, 2)
+	first := ts.TestCases[0]
+	require.Equal(t, "first", first.Name)
+	require.Equal(t, []string{"api", "slow"}, first.Tags)
+	require.Equal(t, 4, first.Steps)
+	require.Equal(t, []string{"value", "echo", "user"}, first.Executors)
+	require.Equal(t, []string{"foo ShouldBeEmpty"}, first.Skip)
+	require.Equal(t, 6, first.Position.Line)
+	second := ts.TestCases[1]
+	require.Equal(t, 2, second.Steps)
+	require.Equal(t, []string{"exec"}, second.Executors)
+	require.NotNil(t, second.Range)
+	require.Empty(t, v.Tests.TestSuites)
+
+	executors, err := v.ListExecutors(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []ListedExecutor{
+		{Name: "custom", Type: "plugin", File: filepath.Join(dir, "lib", "custom.so")},
+		{Name: "echo", Type: "builtin"},
+		{Name: "user", Type: "user", File: filepath.Join(dir, "lib", "user.yml"), Inputs: map[string]interface{}{"url": "http://localhost", "id": nil}, Outputs: []string{"body", "url"}},
+		{Name: "value", Type: "builtin"},
+	}, executors)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"reflect"
 	"strings"
 	"testing"
@@ -17,10 +18,17 @@ func InitTestLogger(t *testing.T) {
 }
 
 var (
-	logger *logrus.Entry
+	// the logs are discarded until InitLogger is called, when venom is used as a library
+	logger = discardLogger()
 	fields = []string{"testsuite", "testcase", "step", "executor"}
 )
 
+func discardLogger() *logrus.Entry {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	return logrus.NewEntry(l)
+}
+
 func fieldsFromContext(ctx context.Context, keys ...string) logrus.Fields {
 	fields := logrus.Fields{}
 	if ctx == nil {
@@ -0,0 +1,93 @@
+package venom
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Position is the position of a testcase, a step, an assertion or an info line in the file it has been read from
+type Position struct {
+	File   string `json:"file" yaml:"file"`
+	Line   int    `json:"line" yaml:"line"`
+	Column int    `json:"column" yaml:"column"`
+}
+
+// stepPosition is the position of a step, and the positions of its assertions, of the assertions of its until condition
+// and of its info lines
+type stepPosition struct {
+	step       *Position
+	assertions []*Position
+	until      []*Position
+	info       []*Position
+}
+
+// nodePosition returns the position of the node n of the file, or nil if n is nil
+func nodePosition(file string, n *yaml.Node) *Position {
+	if n == nil {
+		return nil
+	}
+	return &Position{File: file, Line: n.Line, Column: n.Column}
+}
+
+// newStepPosition returns the positions of the step read from the node n of the file
+func newStepPosition(file string, n *yaml.Node) stepPosition {
+	p := stepPosition{step: nodePosition(file, n)}
+	for _, a := range sequenceItems(mappingValue(n, "assertions")) {
+		p.assertions = append(p.assertions, nodePosition(file, a))
+	}
+	for _, a := range sequenceItems(mappingValue(mappingValue(n, "until"), "assertions")) {
+		p.until = append(p.until, nodePosition(file, a))
+	}
+	// the info can be a single line
+	info := mappingValue(n, "info")
+	if info != nil && info.Kind == yaml.ScalarNode {
+		p.info = append(p.info, nodePosition(file, info))
+	}
+	for _, i := range sequenceItems(info) {
+		p.info = append(p.info, nodePosition(file, i))
+	}
+	return p
+}
+
+// readPositions parses the content of a file to locate its elements.
+// The content as written in the file is used when it is valid yaml, the interpolated content otherwise.
+func readPositions(contents ...[]byte) *yaml.Node {
+	for _, content := range contents {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(content, &doc); err != nil || len(doc.Content) == 0 {
+			continue
+		}
+		if root := doc.Content[0]; root.Kind == yaml.MappingNode {
+			return root
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value of the key in the mapping n, or nil
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// sequenceItems returns the items of the sequence n, or nil
+func sequenceItems(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+// item returns the i-th item of nodes, or nil
+func item(nodes []*yaml.Node, i int) *yaml.Node {
+	if i < 0 || i >= len(nodes) {
+		return nil
+	}
+	return nodes[i]
+}
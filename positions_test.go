@@ -0,0 +1,95 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessPositions(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"suite.yml": `name: suite
+testcases:
+- name: first
+  steps:
+  - type: echo
+    value: a
+- name: first
+  steps:
+  - type: echo
+    value: "{{.value}}"
+    range: [a, b]
+    info:
+    - "value {{.result.value}}"
+    assertions:
+    - result.value ShouldNotBeEmpty
+    - result.value ShouldEqual a
+  - include: steps.yml
+  - type: user
+    value: b
+`,
+		"steps.yml": `steps:
+- type: echo
+  value: c
+  info: "included {{.result.value}}"
+  assertions:
+  - result.value ShouldEqual d
+`,
+		"lib/user.yml": `executor: user
+input:
+  value: a
+steps:
+- type: echo
+  value: '{{.input.value}}'
+  assertions:
+  - result.value ShouldEqual a
+`,
+	})
+
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.LibDir = filepath.Join(dir, "lib")
+	suite := filepath.Join(dir, "suite.yml")
+	_, err := v.Run(context.Background(), []string{suite})
+	require.NoError(t, err)
+
+	ts := v.Tests.TestSuites[0]
+	require.Len(t, ts.TestCases, 2)
+	require.Equal(t, &Position{File: suite, Line: 3, Column: 3}, ts.TestCases[0].Position)
+	require.Equal(t, &Position{File: suite, Line: 7, Column: 3}, ts.TestCases[1].Position)
+	require.Equal(t, &Position{File: suite, Line: 5, Column: 5}, ts.TestCases[0].TestStepResults[0].Position)
+
+	results := ts.TestCases[1].TestStepResults
+	require.Len(t, results, 4)
+
+	// the ranged step fails on its second assertion for the second item
+	require.Equal(t, &Position{File: suite, Line: 9, Column: 5}, results[0].Position)
+	require.Equal(t, results[0].Position, results[1].Position)
+	require.Empty(t, results[0].Errors)
+	require.Equal(t, []string{"value a (suite.yml:13)"}, results[0].ComputedInfo)
+	require.Len(t, results[1].Errors, 1)
+	require.Equal(t, &Position{File: suite, Line: 16, Column: 7}, results[1].Errors[0].Position)
+	require.Contains(t, results[1].Errors[0].Value, "(suite.yml:16)")
+
+	// the steps of the included files are located in these files
+	steps := filepath.Join(dir, "steps.yml")
+	require.Equal(t, &Position{File: steps, Line: 2, Column: 3}, results[2].Position)
+	require.Equal(t, []string{"included c (" + steps + ":4)"}, results[2].ComputedInfo)
+	require.Equal(t, &Position{File: steps, Line: 6, Column: 5}, results[2].Errors[0].Position)
+	require.Contains(t, results[2].Errors[0].Value, "("+steps+":6)")
+
+	// the failures of the steps of the user executors are located in the file of the executor
+	executor := filepath.Join(dir, "lib", "user.yml")
+	require.Equal(t, &Position{File: suite, Line: 18, Column: 5}, results[3].Position)
+	require.NotEmpty(t, results[3].Errors)
+	require.Equal(t, &Position{File: executor, Line: 8, Column: 5}, results[3].Errors[0].Position)
+	require.Contains(t, results[3].Errors[0].Value, "("+executor+":8)")
+
+	// the positions are written in the json report
+	btes, err := json.Marshal(results[1])
+	require.NoError(t, err)
+	require.Contains(t, string(btes), `"position":{"file":"`+suite+`","line":9,"column":5}`)
+	require.Contains(t, string(btes), `"position":{"file":"`+suite+`","line":16,"column":7}`)
+}
@@ -1,11 +1,14 @@
 package venom
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	nested "github.com/antonfisher/nested-logrus-formatter"
@@ -84,6 +87,37 @@ func (v *Venom) Parse(ctx context.Context, path []string) error {
 	if err != nil {
 		return err
 	}
+	if filesPath, err = v.withoutQuarantineFile(filesPath); err != nil {
+		return err
+	}
+	if v.Shard != nil {
+		if filesPath, err = v.shardFiles(ctx, filesPath); err != nil {
+			return err
+		}
+		v.Tests.Shard = v.Shard
+	}
+
+	v.runFilter = nil
+	if v.RunFilter != "" {
+		if v.runFilter, err = regexp.Compile(v.RunFilter); err != nil {
+			return errors.Wrapf(err, "invalid run filter %q", v.RunFilter)
+		}
+	}
+	v.previous = nil
+	if v.RerunFailed != "" {
+		if v.previous, err = readPreviousResults(v.RerunFailed); err != nil {
+			return err
+		}
+	}
+	if v.quarantine, err = compileQuarantine(v.Quarantine); err != nil {
+		return err
+	}
+	if v.tagsFilter, err = parseTagExpression(v.Tags); err != nil {
+		return err
+	}
+	if v.excludeTagsFilter, err = parseTagExpression(v.ExcludeTags); err != nil {
+		return err
+	}
 
 	if err := v.readFiles(ctx, filesPath); err != nil {
 		return err
@@ -94,6 +128,20 @@ func (v *Venom) Parse(ctx context.Context, path []string) error {
 		return errors.Wrapf(err, "unable to register user executors")
 	}
 
+	reallyMissingVars, err := v.missingVariables(ctx)
+	if err != nil {
+		return err
+	}
+	if len(reallyMissingVars) > 0 {
+		return fmt.Errorf("missing variables %v", reallyMissingVars)
+	}
+
+	return nil
+}
+
+// missingVariables parses the testsuites, and returns the variables used in their steps
+// which are neither defined nor extracted from the results of the steps
+func (v *Venom) missingVariables(ctx context.Context) ([]string, error) {
 	missingVars := []string{}
 	extractedVars := []string{}
 	for i := range v.Tests.TestSuites {
@@ -103,7 +151,7 @@ func (v *Venom) Parse(ctx context.Context, path []string) error {
 		Info(ctx, "Parsing testsuite %s", ts.Filepath)
 		tvars, textractedVars, err := v.parseTestSuite(ts)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for k := range ts.Vars {
@@ -140,7 +188,7 @@ func (v *Venom) Parse(ctx context.Context, path []string) error {
 
 	vars, err := DumpStringPreserveCase(v.variables)
 	if err != nil {
-		return errors.Wrapf(err, "unable to parse variables")
+		return nil, errors.Wrapf(err, "unable to parse variables")
 	}
 
 	reallyMissingVars := []string{}
@@ -171,11 +219,7 @@ func (v *Venom) Parse(ctx context.Context, path []string) error {
 		}
 	}
 
-	if len(reallyMissingVars) > 0 {
-		return fmt.Errorf("missing variables %v", reallyMissingVars)
-	}
-
-	return nil
+	return reallyMissingVars, nil
 }
 
 // Process runs tests suite and return a Tests result
@@ -183,39 +227,131 @@ func (v *Venom) Process(ctx context.Context, path []string) error {
 	v.Tests.Status = StatusRun
 	v.Tests.Start = time.Now()
 	Debug(ctx, "nb testsuites: %d", len(v.Tests.TestSuites))
-	for i := range v.Tests.TestSuites {
 
-		v.Tests.TestSuites[i].Start = time.Now()
-		// ##### RUN Test Suite Here
-		if err := v.runTestSuite(ctx, &v.Tests.TestSuites[i]); err != nil {
-			return err
-		}
+	v.events = nil
+	if v.Events != nil {
+		v.events = &eventWriter{w: v.Events, mutex: v.mutex}
+	}
 
-		v.Tests.TestSuites[i].End = time.Now()
-		v.Tests.TestSuites[i].Duration = v.Tests.TestSuites[i].End.Sub(v.Tests.TestSuites[i].Start).Seconds()
+	if v.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, v.Timeout, timeoutError{scope: "run", timeout: v.Timeout})
+		defer cancel()
 	}
+
+	if err := v.runTestSuites(ctx); err != nil {
+		return err
+	}
+
 	v.Tests.End = time.Now()
 	v.Tests.Duration = v.Tests.End.Sub(v.Tests.Start).Seconds()
 
-	var isFailed bool
-	var nSkip int
-	for i := range v.Tests.TestSuites {
-		if v.Tests.TestSuites[i].Status == StatusFail {
-			isFailed = true
-			break
-		} else if v.Tests.TestSuites[i].Status == StatusSkip {
-			nSkip++
-		}
+	if v.RerunMerge && v.previous != nil {
+		v.previous.mergeRerunResults(&v.Tests)
+	} else {
+		v.Tests.computeStatus()
 	}
-	if isFailed {
+	// the testsuites not run because of the cancellation are skipped, but the run fails
+	if err := context.Cause(ctx); err != nil {
+		Error(ctx, "%v", err)
 		v.Tests.Status = StatusFail
-	} else if nSkip > 0 && nSkip == len(v.Tests.TestSuites) {
-		v.Tests.Status = StatusSkip
-	} else {
-		v.Tests.Status = StatusPass
 	}
 
 	Debug(ctx, "final status: %s", v.Tests.Status)
+	if v.events != nil {
+		v.events.onRunEnd(ctx, &v.Tests)
+	}
+
+	return nil
+}
+
+// timeoutError is the cause of the cancellation of a run, a testsuite or a testcase which has timed out
+type timeoutError struct {
+	scope   string
+	timeout time.Duration
+}
+
+func (e timeoutError) Error() string {
+	return fmt.Sprintf("%s timeout of %s exceeded", e.scope, e.timeout)
+}
+
+// InterruptedError is the cause of the cancellation of a run interrupted by a signal
+type InterruptedError struct {
+	Signal os.Signal
+}
+
+func (e InterruptedError) Error() string {
+	return fmt.Sprintf("interrupted by signal %v", e.Signal)
+}
+
+// runTestSuites runs all the testsuites, using up to v.Parallel workers.
+// A testsuite flagged as serial waits for the running testsuites and then runs alone.
+func (v *Venom) runTestSuites(ctx context.Context) error {
+	if v.Parallel <= 1 {
+		for i := range v.Tests.TestSuites {
+			if err := v.processTestSuite(ctx, &v.Tests.TestSuites[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
+	var wg sync.WaitGroup
+	var errs []error
+	var errsMutex sync.Mutex
+	workers := make(chan struct{}, v.Parallel)
+
+	for i := range v.Tests.TestSuites {
+		ts := &v.Tests.TestSuites[i]
+		if ts.Serial {
+			wg.Wait()
+			// no other testsuite is running, the errors of the previous ones are kept
+			if err := v.processTestSuite(ctx, ts); err != nil {
+				errs = append(errs, err)
+				break
+			}
+			continue
+		}
+
+		workers <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			// the output of the testsuite is buffered to not be interleaved with the other ones
+			var buf bytes.Buffer
+			err := v.withOutput(&buf).processTestSuite(ctx, ts)
+
+			v.mutex.Lock()
+			v.Print("%s", buf.String())
+			v.mutex.Unlock()
+
+			if err != nil {
+				errsMutex.Lock()
+				errs = append(errs, err)
+				errsMutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// processTestSuite runs a testsuite and computes its duration
+func (v *Venom) processTestSuite(ctx context.Context, ts *TestSuite) error {
+	ts.Start = time.Now()
+	// ##### RUN Test Suite Here
+	if err := v.runTestSuite(ctx, ts); err != nil {
+		return err
+	}
+	ts.End = time.Now()
+	ts.Duration = ts.End.Sub(ts.Start).Seconds()
 	return nil
 }
@@ -0,0 +1,197 @@
+package venom
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gosimple/slug"
+)
+
+// resolveDependencies resolves the depends_on attribute of the testcases of the suite,
+// and computes the order in which they have to run.
+// It returns an error if a testcase depends on an unknown testcase, or on a cycle.
+func (ts *TestSuite) resolveDependencies() error {
+	// a ranged testcase is known by the name of each of its items, and by its name which refers to all its items
+	index := make(map[string][]int, 2*len(ts.TestCases))
+	for i, tc := range ts.TestCases {
+		for _, name := range []string{tc.originalName, tc.computedVarsPrefix(), tc.Name} {
+			if !slices.Contains(index[name], i) {
+				index[name] = append(index[name], i)
+			}
+		}
+	}
+
+	for i := range ts.TestCases {
+		tc := &ts.TestCases[i]
+		tc.dependencies = nil
+		for _, name := range tc.DependsOn {
+			dependencies, ok := index[name]
+			if !ok {
+				dependencies, ok = index[slug.Make(name)]
+			}
+			if !ok {
+				return fmt.Errorf("testcase %q of testsuite %q depends on unknown testcase %q", tc.originalName, ts.Name, name)
+			}
+			tc.dependencies = append(tc.dependencies, dependencies...)
+		}
+	}
+
+	// depth-first walk of the dependencies, keeping the order of the file between independent testcases
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(ts.TestCases))
+	order := make([]int, 0, len(ts.TestCases))
+	var path []int
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			var names []string
+			for _, j := range path[slices.Index(path, i):] {
+				names = append(names, ts.TestCases[j].Name)
+			}
+			names = append(names, ts.TestCases[i].Name)
+			return fmt.Errorf("dependency cycle between testcases of testsuite %q: %s", ts.Name, strings.Join(names, " -> "))
+		}
+		state[i] = visiting
+		path = append(path, i)
+		for _, j := range ts.TestCases[i].dependencies {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		order = append(order, i)
+		return nil
+	}
+	for i := range ts.TestCases {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	ts.order = order
+	return nil
+}
+
+// testCasesOrder returns the indexes of the testcases in the order they have to run
+func (ts *TestSuite) testCasesOrder() []int {
+	if len(ts.order) == len(ts.TestCases) {
+		return ts.order
+	}
+	order := make([]int, len(ts.TestCases))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// hasDependencies returns true if at least one testcase of the suite depends on another one
+func (ts *TestSuite) hasDependencies() bool {
+	for _, tc := range ts.TestCases {
+		if len(tc.dependencies) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// selectWithDependencies adds the i-th testcase and the testcases it depends on, transitively, to selected
+func (ts *TestSuite) selectWithDependencies(selected map[int]struct{}, i int) {
+	if _, ok := selected[i]; ok {
+		return
+	}
+	selected[i] = struct{}{}
+	for _, j := range ts.TestCases[i].dependencies {
+		ts.selectWithDependencies(selected, j)
+	}
+}
+
+// skipOnFailedDependencies skips the testcase if one of its dependencies failed or was skipped
+func (ts *TestSuite) skipOnFailedDependencies(tc *TestCase) {
+	if len(tc.Skipped) > 0 {
+		return
+	}
+	for _, j := range tc.dependencies {
+		dependency := ts.TestCases[j]
+		switch {
+		case dependency.Status == StatusFail || (dependency.Status == StatusQuarantined && dependency.Quarantine.Status == StatusFail):
+			tc.Skipped = append(tc.Skipped, Skipped{Value: fmt.Sprintf("===== dependency %s failed =====", dependency.Name)})
+		case dependency.Status == StatusSkip:
+			tc.Skipped = append(tc.Skipped, Skipped{Value: fmt.Sprintf("===== dependency %s skipped =====", dependency.Name)})
+		}
+	}
+}
+
+// runTestCasesInParallel runs each testcase of the suite as soon as its dependencies are over,
+// using up to v.Parallel workers
+func (v *Venom) runTestCasesInParallel(ctx context.Context, ts *TestSuite) {
+	remaining := make([]int, len(ts.TestCases))
+	dependents := make([][]int, len(ts.TestCases))
+	var ready []int
+	for _, i := range ts.testCasesOrder() {
+		remaining[i] = len(ts.TestCases[i].dependencies)
+		for _, j := range ts.TestCases[i].dependencies {
+			dependents[j] = append(dependents[j], i)
+		}
+		if remaining[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	done := make(chan int)
+	var running int
+	var stopped bool
+	for {
+		for running < v.Parallel && len(ready) > 0 && !stopped {
+			i := ready[0]
+			ready = ready[1:]
+			tc := &ts.TestCases[i]
+			ts.skipOnFailedDependencies(tc)
+
+			// the testcase runs against a copy of the suite, taken with the variables computed so far
+			tsCopy := *ts
+			tsCopy.ComputedVars = ts.ComputedVars.Clone()
+			running++
+			go func() {
+				// the output of the testcase is buffered to not be interleaved with the other ones
+				var buf bytes.Buffer
+				v.withOutput(&buf).processTestCase(ctx, &tsCopy, tc)
+
+				v.mutex.Lock()
+				v.Print("%s", buf.String())
+				v.mutex.Unlock()
+				done <- i
+			}()
+		}
+		if running == 0 {
+			break
+		}
+
+		i := <-done
+		running--
+		tc := &ts.TestCases[i]
+		if v.StopOnFailure && tc.Status != StatusQuarantined && tc.hasFailedStep() {
+			stopped = true
+		}
+		ts.ComputedVars.AddAllWithPrefix(tc.computedVarsPrefix(), tc.computedVars)
+		for _, j := range dependents[i] {
+			remaining[j]--
+			if remaining[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+
+	if stopped {
+		ts.skipRemainingTestCases()
+	}
+}
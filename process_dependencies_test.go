@@ -0,0 +1,234 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDependencies(t *testing.T) {
+	ts := &TestSuite{Name: "suite", TestCases: []TestCase{
+		{TestCaseInput: TestCaseInput{Name: "use-account", DependsOn: []string{"create-account"}}},
+		{TestCaseInput: TestCaseInput{Name: "create-account", DependsOn: []string{"create-user"}}},
+		{TestCaseInput: TestCaseInput{Name: "create-user"}},
+		{TestCaseInput: TestCaseInput{Name: "independent"}},
+	}}
+	require.NoError(t, ts.resolveDependencies())
+	require.Equal(t, []int{2, 1, 0, 3}, ts.testCasesOrder())
+
+	selected := map[int]struct{}{}
+	ts.selectWithDependencies(selected, 0)
+	require.Equal(t, map[int]struct{}{0: {}, 1: {}, 2: {}}, selected)
+
+	ts.TestCases[2].DependsOn = []string{"use-account"}
+	require.ErrorContains(t, ts.resolveDependencies(), "cycle")
+}
+
+func TestProcessDependsOn(t *testing.T) {
+	suite := `name: suite
+testcases:
+- name: use account
+  depends_on: [create-account]
+  steps:
+  - type: echo
+    value: "{{.create-account.id}}"
+    assertions:
+    - result.value ShouldEqual 42
+- name: create-account
+  steps:
+  - type: echo
+    value: 42
+    vars:
+      id:
+        from: result.value
+- name: failing
+  depends_on: [create account]
+  steps:
+  - type: echo
+    value: foo
+    assertions:
+    - result.value ShouldEqual bar
+- name: after-failing
+  depends_on: [failing]
+  steps:
+  - type: echo
+    value: foo
+- name: after-after-failing
+  depends_on: [after-failing, use account]
+  steps:
+  - type: echo
+    value: foo
+`
+	for _, parallel := range []int{1, 3} {
+		t.Run(fmt.Sprintf("parallel %d", parallel), func(t *testing.T) {
+			v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+			v.Parallel = parallel
+			runTestSuites(t, v, suite)
+
+			ts := v.Tests.TestSuites[0]
+			require.Equal(t, StatusFail, ts.Status)
+			require.Equal(t, StatusPass, ts.TestCases[0].Status)
+			require.True(t, ts.TestCases[0].Start.After(ts.TestCases[1].End))
+			require.Equal(t, StatusPass, ts.TestCases[1].Status)
+			require.Equal(t, StatusFail, ts.TestCases[2].Status)
+			require.Equal(t, StatusSkip, ts.TestCases[3].Status)
+			require.Equal(t, "===== dependency failing failed =====", ts.TestCases[3].Skipped[0].Value)
+			require.Equal(t, StatusSkip, ts.TestCases[4].Status)
+			require.Equal(t, "===== dependency after-failing skipped =====", ts.TestCases[4].Skipped[0].Value)
+		})
+	}
+}
+
+func TestProcessDependsOnParallel(t *testing.T) {
+	e := &concurrencyExecutor{}
+	v := newTestVenom(t, map[string]Executor{"concurrency": e})
+	v.Parallel = 2
+	runTestSuites(t, v, `name: suite
+testcases:
+- name: a
+  steps:
+  - type: concurrency
+- name: b
+  steps:
+  - type: concurrency
+- name: c
+  steps:
+  - type: concurrency
+- name: d
+  depends_on: [a, b, c]
+  steps:
+  - type: concurrency
+`)
+
+	ts := v.Tests.TestSuites[0]
+	require.Equal(t, StatusPass, ts.Status)
+	require.Equal(t, int32(2), e.max)
+	for _, tc := range ts.TestCases[:3] {
+		require.True(t, ts.TestCases[3].Start.After(tc.End))
+	}
+}
+
+func TestParseDependsOnErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		suite   string
+		wantErr string
+	}{
+		{
+			name: "unknown testcase",
+			suite: `name: suite
+testcases:
+- name: a
+  depends_on: [unknown]
+  steps:
+  - type: echo
+`,
+			wantErr: `testcase "a" of testsuite "suite" depends on unknown testcase "unknown"`,
+		},
+		{
+			name: "cycle",
+			suite: `name: suite
+testcases:
+- name: a
+  depends_on: [c]
+  steps:
+  - type: echo
+- name: b
+  depends_on: [a]
+  steps:
+  - type: echo
+- name: c
+  depends_on: [b]
+  steps:
+  - type: echo
+`,
+			wantErr: `dependency cycle between testcases of testsuite "suite": a -> c -> b -> a`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+			dir := writeTestSuites(t, tt.suite)
+			require.EqualError(t, v.Parse(context.Background(), []string{dir}), tt.wantErr)
+		})
+	}
+}
+
+func TestProcessRunFilterDependsOn(t *testing.T) {
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.RunFilter = "use-account"
+	runTestSuites(t, v, `name: suite
+testcases:
+- name: create-user
+  steps:
+  - type: echo
+    value: user
+- name: create-account
+  depends_on: [create-user]
+  steps:
+  - type: echo
+    value: account
+    vars:
+      id:
+        from: result.value
+- name: other
+  steps:
+  - type: echo
+    value: other
+- name: use-account
+  depends_on: [create-account]
+  steps:
+  - type: echo
+    value: "{{.create-account.id}}"
+    assertions:
+    - result.value ShouldEqual account
+`)
+
+	ts := v.Tests.TestSuites[0]
+	require.Equal(t, StatusPass, ts.Status)
+	require.Equal(t, StatusPass, ts.TestCases[0].Status)
+	require.Equal(t, StatusPass, ts.TestCases[1].Status)
+	require.Equal(t, StatusSkip, ts.TestCases[2].Status)
+	require.Equal(t, `===== suite/other does not match "use-account" =====`, ts.TestCases[2].Skipped[0].Value)
+	require.Equal(t, StatusPass, ts.TestCases[3].Status)
+}
+
+func TestProcessTagsDependsOn(t *testing.T) {
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.Tags = "smoke"
+	v.ExcludeTags = "slow"
+	runTestSuites(t, v, `name: suite
+testcases:
+- name: create-user
+  steps:
+  - type: echo
+    value: user
+- name: create-account
+  tags: [slow]
+  depends_on: [create-user]
+  steps:
+  - type: echo
+    value: account
+- name: other
+  steps:
+  - type: echo
+    value: other
+- name: use-account
+  tags: [smoke]
+  depends_on: [create-account]
+  steps:
+  - type: echo
+    value: account
+`)
+
+	// the dependencies of the tagged testcases are run, even if they are excluded
+	ts := v.Tests.TestSuites[0]
+	require.Equal(t, StatusPass, ts.Status)
+	require.Equal(t, StatusPass, ts.TestCases[0].Status)
+	require.Equal(t, StatusPass, ts.TestCases[1].Status)
+	require.Equal(t, StatusSkip, ts.TestCases[2].Status)
+	require.Equal(t, `===== tags [] do not match "smoke" =====`, ts.TestCases[2].Skipped[0].Value)
+	require.Equal(t, StatusPass, ts.TestCases[3].Status)
+}
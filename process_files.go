@@ -70,44 +70,11 @@ func (v *Venom) readFiles(ctx context.Context, filesPath []string) (err error) {
 		}
 
 		varCloned := v.variables.Clone()
-
-		fromPartial, err := getVarFromPartialYML(ctx, btes)
-		if err != nil {
-			return errors.Wrapf(err, "unable to get vars from file %q", filePath)
-		}
-
-		var varsFromPartial map[string]string
-		if len(fromPartial) > 0 {
-			varsFromPartial, err = DumpStringPreserveCase(fromPartial)
-			if err != nil {
-				return errors.Wrapf(err, "unable to parse variables")
-			}
+		if v.previous != nil {
+			// rerun with the variables of the previous run
+			v.previous.addVars(filePath, varCloned)
 		}
-
-		// we take default vars from the testsuite, only if it's not already is global vars
-		for k, value := range varsFromPartial {
-			if k == "" {
-				continue
-			}
-			if _, ok := varCloned[k]; !ok || (varCloned[k] == "{}" && varCloned["__Len__"] == "0") {
-				// we interpolate the value of vars here, to do it only once per ts
-				valueInterpolated, err := interpolate.Do(value, varsFromPartial)
-				if err != nil {
-					return errors.Wrapf(err, "unable to parse variable %q", k)
-				}
-				varCloned.Add(k, valueInterpolated)
-			}
-		}
-
-		var vars map[string]string
-		if len(varCloned) > 0 {
-			vars, err = DumpStringPreserveCase(varCloned)
-			if err != nil {
-				return errors.Wrapf(err, "unable to parse variables")
-			}
-		}
-
-		content, err := interpolate.Do(string(btes), vars)
+		content, err := interpolateFile(ctx, filePath, btes, varCloned)
 		if err != nil {
 			return err
 		}
@@ -118,16 +85,51 @@ func (v *Venom) readFiles(ctx context.Context, filesPath []string) (err error) {
 			return errors.Wrapf(err, "error while unmarshal file %q", filePath)
 		}
 
+		root := readPositions(btes, []byte(content))
 		ts := TestSuite{
 			Name:        testSuiteInput.Name,
 			Description: testSuiteInput.Description,
-			TestCases:   make([]TestCase, len(testSuiteInput.TestCases)),
 			Vars:        testSuiteInput.Vars,
 			Secrets:     testSuiteInput.Secrets,
+			Serial:      testSuiteInput.Serial,
+			Tags:        testSuiteInput.Tags,
+			Timeout:     testSuiteInput.Timeout,
+		}
+
+		// the included testcases come first, then the testcases of the file
+		reader := newIncludeReader(ctx, filePath)
+		importedVars := H{}
+		ts.TestCases, err = reader.includeTestCases(filePath, testSuiteInput.Include, varCloned, importedVars)
+		if err != nil {
+			return err
+		}
+		tcNodes := sequenceItems(mappingValue(root, "testcases"))
+		for i, input := range testSuiteInput.TestCases {
+			tc, err := reader.newTestCase(filePath, input, varCloned, newTestCaseNodes(item(tcNodes, i)))
+			if err != nil {
+				return err
+			}
+			ts.TestCases = append(ts.TestCases, tc)
+		}
+		if len(testSuiteInput.Setup) > 0 {
+			nodes := testCaseNodes{testcase: mappingValue(root, "setup"), steps: sequenceItems(mappingValue(root, "setup"))}
+			setup, err := reader.newTestCase(filePath, TestCaseInput{Name: "setup", RawTestSteps: testSuiteInput.Setup}, varCloned, nodes)
+			if err != nil {
+				return err
+			}
+			ts.Setup = &setup
+		}
+		if len(testSuiteInput.Teardown) > 0 {
+			nodes := testCaseNodes{testcase: mappingValue(root, "teardown"), steps: sequenceItems(mappingValue(root, "teardown"))}
+			teardown, err := reader.newTestCase(filePath, TestCaseInput{Name: "teardown", RawTestSteps: testSuiteInput.Teardown}, varCloned, nodes)
+			if err != nil {
+				return err
+			}
+			ts.Teardown = &teardown
 		}
-		for i := range testSuiteInput.TestCases {
-			ts.TestCases[i] = TestCase{
-				TestCaseInput: testSuiteInput.TestCases[i],
+		for k, value := range importedVars {
+			if _, ok := varCloned[k]; !ok {
+				varCloned.Add(k, value)
 			}
 		}
 		Info(ctx, "Has %d Secrets", len(ts.Secrets))
@@ -162,3 +164,45 @@ func (v *Venom) readFiles(ctx context.Context, filesPath []string) (err error) {
 	}
 	return nil
 }
+
+// interpolateFile interpolates the content of a file with vars.
+// The default variables of the file are added to vars, if they are not already defined.
+func interpolateFile(ctx context.Context, filePath string, btes []byte, vars H) (string, error) {
+	fromPartial, err := getVarFromPartialYML(ctx, btes)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to get vars from file %q", filePath)
+	}
+
+	var varsFromPartial map[string]string
+	if len(fromPartial) > 0 {
+		varsFromPartial, err = DumpStringPreserveCase(fromPartial)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to parse variables")
+		}
+	}
+
+	// we take default vars from the testsuite, only if it's not already is global vars
+	for k, value := range varsFromPartial {
+		if k == "" {
+			continue
+		}
+		if _, ok := vars[k]; !ok || (vars[k] == "{}" && vars["__Len__"] == "0") {
+			// we interpolate the value of vars here, to do it only once per ts
+			valueInterpolated, err := interpolate.Do(value, varsFromPartial)
+			if err != nil {
+				return "", errors.Wrapf(err, "unable to parse variable %q", k)
+			}
+			vars.Add(k, valueInterpolated)
+		}
+	}
+
+	var dumpedVars map[string]string
+	if len(vars) > 0 {
+		dumpedVars, err = DumpStringPreserveCase(vars)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to parse variables")
+		}
+	}
+
+	return interpolate.Do(string(btes), dumpedVars)
+}
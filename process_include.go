@@ -0,0 +1,246 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rockbears/yaml"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// Include is an include directive: the file to include, relative to the including file,
+// and the input overriding the variables of the included file
+type Include struct {
+	File  string `json:"file" yaml:"file"`
+	Input H      `json:"input,omitempty" yaml:"input,omitempty"`
+}
+
+// UnmarshalJSON allows to write an include directive as the path of the file only
+func (i *Include) UnmarshalJSON(data []byte) error {
+	var file string
+	if err := json.Unmarshal(data, &file); err == nil {
+		*i = Include{File: file}
+		return nil
+	}
+	type include Include
+	return json.Unmarshal(data, (*include)(i))
+}
+
+// Includes is a list of include directives, which can be written as a single include directive
+type Includes []Include
+
+func (i *Includes) UnmarshalJSON(data []byte) error {
+	var includes []Include
+	if err := json.Unmarshal(data, &includes); err == nil {
+		*i = includes
+		return nil
+	}
+	var include Include
+	if err := json.Unmarshal(data, &include); err != nil {
+		return err
+	}
+	*i = Includes{include}
+	return nil
+}
+
+// includedFile is the content of a file included by a testsuite or by a step
+type includedFile struct {
+	Include   Includes          `json:"include" yaml:"include"`
+	Vars      H                 `json:"vars" yaml:"vars"`
+	TestCases []TestCaseInput   `json:"testcases" yaml:"testcases"`
+	Steps     []json.RawMessage `json:"steps" yaml:"steps"`
+
+	// root is the yaml node of the file, to locate its testcases and steps
+	root *yaml3.Node
+}
+
+// stepInclude is a step including the steps of another file
+type stepInclude struct {
+	Include *string `json:"include"`
+	Input   H       `json:"input"`
+}
+
+// stepOrigin locates a step in the file it has been read from
+type stepOrigin struct {
+	// filename is empty when the step comes from the testsuite file
+	filename string
+	position stepPosition
+}
+
+// testCaseNodes are the yaml nodes of a testcase and of its steps, to locate them in their file
+type testCaseNodes struct {
+	testcase *yaml3.Node
+	steps    []*yaml3.Node
+	finally  []*yaml3.Node
+}
+
+func newTestCaseNodes(n *yaml3.Node) testCaseNodes {
+	return testCaseNodes{
+		testcase: n,
+		steps:    sequenceItems(mappingValue(n, "steps")),
+		finally:  sequenceItems(mappingValue(n, "finally")),
+	}
+}
+
+// includeReader reads the files included by a testsuite
+type includeReader struct {
+	ctx      context.Context
+	filePath string
+	// stack contains the absolute path of the files being included, to detect the cycles
+	stack []string
+}
+
+func newIncludeReader(ctx context.Context, filePath string) *includeReader {
+	abs, _ := filepath.Abs(filePath)
+	return &includeReader{ctx: ctx, filePath: filePath, stack: []string{abs}}
+}
+
+// read reads a file included by includingFile, interpolated with vars, the input of the include directive and its default variables.
+// The included file is pushed on the stack: the caller has to pop it once its includes are processed.
+func (r *includeReader) read(includingFile string, include Include, vars H) (string, *includedFile, H, error) {
+	if include.File == "" {
+		return "", nil, nil, fmt.Errorf("missing file to include in %q", includingFile)
+	}
+	filePath := include.File
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(filepath.Dir(includingFile), filePath)
+	}
+
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", nil, nil, errors.Wrapf(err, "unable to include file %q", filePath)
+	}
+	for i, f := range r.stack {
+		if f == abs {
+			var cycle []string
+			for _, f := range append(r.stack[i:], abs) {
+				cycle = append(cycle, filepath.Base(f))
+			}
+			return "", nil, nil, fmt.Errorf("include cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	Info(r.ctx, "Including %v", filePath)
+	btes, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", nil, nil, errors.Wrapf(err, "unable to read file %q included by %q", filePath, includingFile)
+	}
+
+	includeVars := vars.Clone()
+	if includeVars == nil {
+		includeVars = H{}
+	}
+	includeVars.AddAll(include.Input)
+	content, err := interpolateFile(r.ctx, filePath, btes, includeVars)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var f includedFile
+	if err := yaml.Unmarshal([]byte(content), &f); err != nil {
+		Error(r.ctx, "file content: %s", content)
+		return "", nil, nil, errors.Wrapf(err, "error while unmarshal file %q", filePath)
+	}
+	f.root = readPositions(btes, []byte(content))
+
+	r.stack = append(r.stack, abs)
+	return filePath, &f, includeVars, nil
+}
+
+func (r *includeReader) pop() {
+	r.stack = r.stack[:len(r.stack)-1]
+}
+
+// includeTestCases returns the testcases of the files included by includingFile.
+// The variables of the included files are added to importedVars, if they are not already defined.
+func (r *includeReader) includeTestCases(includingFile string, includes Includes, vars H, importedVars H) ([]TestCase, error) {
+	var testCases []TestCase
+	for _, include := range includes {
+		filePath, f, includeVars, err := r.read(includingFile, include, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		nested, err := r.includeTestCases(filePath, f.Include, includeVars, importedVars)
+		if err != nil {
+			return nil, err
+		}
+		testCases = append(testCases, nested...)
+
+		tcNodes := sequenceItems(mappingValue(f.root, "testcases"))
+		for i, input := range f.TestCases {
+			tc, err := r.newTestCase(filePath, input, includeVars, newTestCaseNodes(item(tcNodes, i)))
+			if err != nil {
+				return nil, err
+			}
+			testCases = append(testCases, tc)
+		}
+
+		// the variables are imported as they are defined in the file, or as they are given in the input of the include directive
+		for k, value := range f.Vars {
+			if input, ok := include.Input[k]; ok {
+				value = input
+			}
+			if _, ok := importedVars[k]; !ok {
+				importedVars.Add(k, value)
+			}
+		}
+		r.pop()
+	}
+	return testCases, nil
+}
+
+// newTestCase returns the testcase read from filePath, with the included steps, located by its yaml nodes
+func (r *includeReader) newTestCase(filePath string, input TestCaseInput, vars H, nodes testCaseNodes) (TestCase, error) {
+	tc := TestCase{TestCaseInput: input, Position: nodePosition(filePath, nodes.testcase)}
+	steps, origins, err := r.includeSteps(filePath, input.RawTestSteps, nodes.steps, vars)
+	if err != nil {
+		return tc, err
+	}
+	finallySteps, finallyOrigins, err := r.includeSteps(filePath, input.RawFinallySteps, nodes.finally, vars)
+	if err != nil {
+		return tc, err
+	}
+	tc.RawTestSteps = steps
+	tc.RawFinallySteps = finallySteps
+	tc.stepOrigins = append(origins, finallyOrigins...)
+	return tc, nil
+}
+
+// includeSteps replaces the steps including a file by the steps of this file,
+// and returns where each step comes from
+func (r *includeReader) includeSteps(filePath string, rawSteps []json.RawMessage, nodes []*yaml3.Node, vars H) ([]json.RawMessage, []stepOrigin, error) {
+	filename := filePath
+	if filePath == r.filePath {
+		filename = ""
+	}
+
+	steps := make([]json.RawMessage, 0, len(rawSteps))
+	origins := make([]stepOrigin, 0, len(rawSteps))
+	for i, rawStep := range rawSteps {
+		var include stepInclude
+		if err := json.Unmarshal(rawStep, &include); err != nil || include.Include == nil {
+			steps = append(steps, rawStep)
+			origins = append(origins, stepOrigin{filename: filename, position: newStepPosition(filePath, item(nodes, i))})
+			continue
+		}
+
+		includedPath, f, includeVars, err := r.read(filePath, Include{File: *include.Include, Input: include.Input}, vars)
+		if err != nil {
+			return nil, nil, err
+		}
+		includedSteps, includedOrigins, err := r.includeSteps(includedPath, f.Steps, sequenceItems(mappingValue(f.root, "steps")), includeVars)
+		if err != nil {
+			return nil, nil, err
+		}
+		r.pop()
+		steps = append(steps, includedSteps...)
+		origins = append(origins, includedOrigins...)
+	}
+	return steps, origins, nil
+}
@@ -0,0 +1,131 @@
+package venom
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessInclude(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"suite.yml": `name: suite
+include:
+- file: common/login.yml
+  input:
+    user: admin
+testcases:
+- name: use-token
+  steps:
+  - type: echo
+    value: "{{.login.token}}"
+    assertions:
+    - result.value ShouldEqual admin-token
+  - include: common/check.yml
+    input:
+      expected: "{{.user}}"
+`,
+		"common/login.yml": `vars:
+  user: guest
+testcases:
+- name: login
+  steps:
+  - include: token.yml
+`,
+		"common/token.yml": `steps:
+- type: echo
+  value: "{{.user}}-token"
+  vars:
+    token:
+      from: result.value
+`,
+		"common/check.yml": `vars:
+  expected: nobody
+steps:
+- type: echo
+  value: admin
+  assertions:
+  - result.value ShouldEqual {{.expected}}
+- type: echo
+  value: admin
+  assertions:
+  - result.value ShouldEqual wrong
+`,
+	})
+
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	require.NoError(t, v.Parse(context.Background(), []string{filepath.Join(dir, "suite.yml")}))
+	require.NoError(t, v.Process(context.Background(), nil))
+
+	ts := v.Tests.TestSuites[0]
+	require.Equal(t, "admin", ts.Vars["user"])
+	require.Len(t, ts.TestCases, 2)
+	require.Equal(t, "login", ts.TestCases[0].Name)
+	require.Equal(t, StatusPass, ts.TestCases[0].Status)
+
+	tc := ts.TestCases[1]
+	require.Equal(t, StatusFail, tc.Status)
+	require.Len(t, tc.TestStepResults, 3)
+	require.Equal(t, StatusPass, tc.TestStepResults[0].Status)
+	require.Equal(t, StatusPass, tc.TestStepResults[1].Status)
+	require.Equal(t, StatusFail, tc.TestStepResults[2].Status)
+	require.Contains(t, tc.TestStepResults[2].Errors[0].Value, `Testcase "use-token", step #3-0: Assertion "result.value ShouldEqual wrong" failed.`)
+	require.Contains(t, tc.TestStepResults[2].Errors[0].Value, "("+filepath.Join(dir, "common", "check.yml")+":")
+}
+
+func TestProcessIncludeMapVars(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"suite.yml": `name: suite
+include:
+- common/api.yml
+testcases:
+- name: use-api
+  steps:
+  - type: echo
+    value: "{{.api.url}}/users/{{.api.user}}"
+    assertions:
+    - result.value ShouldEqual http://localhost/users/guest
+`,
+		"common/api.yml": `vars:
+  api:
+    url: http://localhost
+    user: guest
+testcases:
+- name: ping
+  steps:
+  - type: echo
+    value: "{{.api.url}}"
+`,
+	})
+
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	require.NoError(t, v.Parse(context.Background(), []string{filepath.Join(dir, "suite.yml")}))
+	require.Equal(t, map[string]interface{}{"url": "http://localhost", "user": "guest"}, v.Tests.TestSuites[0].Vars["api"])
+	require.NotContains(t, v.Tests.TestSuites[0].Vars, "api.url")
+	require.NoError(t, v.Process(context.Background(), nil))
+
+	ts := v.Tests.TestSuites[0]
+	require.Equal(t, StatusPass, ts.TestCases[0].Status)
+	require.Equal(t, StatusPass, ts.TestCases[1].Status)
+}
+
+func TestParseIncludeCycle(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"suite.yml": `name: suite
+testcases:
+- name: testcase
+  steps:
+  - include: lib/a.yml
+`,
+		"lib/a.yml": `steps:
+- include: b.yml
+`,
+		"lib/b.yml": `steps:
+- include: a.yml
+`,
+	})
+
+	v := newTestVenom(t, nil)
+	require.EqualError(t, v.Parse(context.Background(), []string{filepath.Join(dir, "suite.yml")}), "include cycle: a.yml -> b.yml -> a.yml")
+}
@@ -0,0 +1,308 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyExecutor records the maximum number of steps running at the same time
+type concurrencyExecutor struct {
+	running int32
+	max     int32
+}
+
+func (e *concurrencyExecutor) Run(ctx context.Context, step TestStep) (interface{}, error) {
+	n := atomic.AddInt32(&e.running, 1)
+	defer atomic.AddInt32(&e.running, -1)
+	for {
+		max := atomic.LoadInt32(&e.max)
+		if n <= max || atomic.CompareAndSwapInt32(&e.max, max, n) {
+			break
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	return map[string]interface{}{"code": 0}, nil
+}
+
+func writeTestSuites(t *testing.T, contents ...string) string {
+	dir := t.TempDir()
+	for i, content := range contents {
+		filename := filepath.Join(dir, fmt.Sprintf("testsuite_%d.yml", i))
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0o644))
+	}
+	return dir
+}
+
+// writeFiles writes the files in a temporary directory, and returns its path
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o755))
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0o644))
+	}
+	return dir
+}
+
+// funcExecutor is an executor calling a function to run the steps
+type funcExecutor func(ctx context.Context, step TestStep) (interface{}, error)
+
+func (f funcExecutor) Run(ctx context.Context, step TestStep) (interface{}, error) {
+	return f(ctx, step)
+}
+
+// echoExecutor returns the value attribute of the step as result.value
+var echoExecutor = funcExecutor(func(ctx context.Context, step TestStep) (interface{}, error) {
+	return map[string]interface{}{"result": map[string]interface{}{"value": step["value"]}}, nil
+})
+
+func newTestVenom(t *testing.T, executors map[string]Executor) *Venom {
+	InitTestLogger(t)
+	v := New()
+	v.PrintFunc = func(format string, a ...interface{}) (int, error) {
+		return 0, nil
+	}
+	for name, e := range executors {
+		v.RegisterExecutorBuiltin(name, e)
+	}
+	return v
+}
+
+// testSuiteByName returns the testsuite named name, the testsuites being read in any order
+func testSuiteByName(t *testing.T, v *Venom, name string) *TestSuite {
+	for i := range v.Tests.TestSuites {
+		if v.Tests.TestSuites[i].Name == name {
+			return &v.Tests.TestSuites[i]
+		}
+	}
+	require.Failf(t, "testsuite not found", "no testsuite named %q", name)
+	return nil
+}
+
+func runTestSuites(t *testing.T, v *Venom, contents ...string) {
+	dir := writeTestSuites(t, contents...)
+	require.NoError(t, v.Parse(context.Background(), []string{dir}))
+	require.NoError(t, v.Process(context.Background(), []string{dir}))
+}
+
+func TestProcessParallel(t *testing.T) {
+	suite := `name: suite %d
+serial: %t
+testcases:
+- name: testcase
+  steps:
+  - type: concurrency
+`
+	tests := []struct {
+		name     string
+		parallel int
+		serial   bool
+		wantMax  int32
+	}{
+		{name: "sequential", parallel: 1, wantMax: 1},
+		{name: "parallel", parallel: 3, wantMax: 3},
+		{name: "parallel with serial testsuites", parallel: 3, serial: true, wantMax: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &concurrencyExecutor{}
+			v := newTestVenom(t, map[string]Executor{"concurrency": e})
+			v.Parallel = tt.parallel
+
+			var contents []string
+			for i := 0; i < 6; i++ {
+				contents = append(contents, fmt.Sprintf(suite, i, tt.serial))
+			}
+			runTestSuites(t, v, contents...)
+
+			require.Equal(t, StatusPass, v.Tests.Status)
+			require.Equal(t, 6, v.Tests.NbTestsuitesPass)
+			require.Equal(t, tt.wantMax, e.max)
+			for _, ts := range v.Tests.TestSuites {
+				require.Equal(t, StatusPass, ts.Status)
+			}
+		})
+	}
+}
+
+func TestProcessParallelSerialError(t *testing.T) {
+	e := &concurrencyExecutor{}
+	v := newTestVenom(t, map[string]Executor{"concurrency": e})
+	v.Parallel = 3
+	dir := writeTestSuites(t, `name: first
+testcases:
+- name: testcase
+  steps:
+  - type: concurrency
+`, `name: before
+testcases:
+- name: testcase
+  steps:
+  - type: concurrency
+`, `name: serial
+serial: true
+testcases:
+- name: testcase
+  steps:
+  - type: concurrency
+`, `name: after
+testcases:
+- name: testcase
+  steps:
+  - type: concurrency
+`)
+	// the testsuites are run in the order of the paths
+	var paths []string
+	for i := 0; i < 4; i++ {
+		paths = append(paths, filepath.Join(dir, fmt.Sprintf("testsuite_%d.yml", i)))
+	}
+	require.NoError(t, v.Parse(context.Background(), paths))
+	// the first testsuite and the serial one fail once started, the error of the first one is returned
+	testSuiteByName(t, v, "first").Vars.Add("early", "{{ .early }")
+	testSuiteByName(t, v, "serial").Vars.Add("broken", "{{ .broken }")
+	err := v.Process(context.Background(), paths)
+	require.ErrorContains(t, err, "error while computing variable early")
+
+	// the testsuites run before the serial one are over, the next ones are not run
+	require.Zero(t, atomic.LoadInt32(&e.running))
+	require.Equal(t, StatusPass, testSuiteByName(t, v, "before").TestCases[0].Status)
+	require.Empty(t, testSuiteByName(t, v, "after").TestCases[0].Status)
+}
+
+// sleepExecutor sleeps for the duration of the step, or until its context is cancelled
+var sleepExecutor = funcExecutor(func(ctx context.Context, step TestStep) (interface{}, error) {
+	duration, err := step.DurationValue("duration")
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(duration):
+		return map[string]interface{}{"result": map[string]interface{}{}}, nil
+	}
+})
+
+func TestProcessTimeout(t *testing.T) {
+	v := newTestVenom(t, map[string]Executor{"sleep": sleepExecutor, "echo": echoExecutor})
+	start := time.Now()
+	runTestSuites(t, v, `name: testcase timeout
+testcases:
+- name: slow
+  timeout: 50ms
+  steps:
+  - type: sleep
+    duration: 10s
+  - type: echo
+    value: not run
+  finally:
+  - type: echo
+    value: cleanup
+- name: next
+  steps:
+  - type: echo
+    value: run
+`, `name: testsuite timeout
+timeout: 100ms
+testcases:
+- name: slow
+  steps:
+  - type: sleep
+    duration: 10s
+- name: next
+  steps:
+  - type: echo
+    value: not run
+teardown:
+- type: echo
+  value: cleanup
+`)
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	ts := testSuiteByName(t, v, "testcase timeout")
+	slow := ts.TestCases[0]
+	require.Equal(t, StatusFail, slow.Status)
+	require.Len(t, slow.TestStepResults, 2)
+	require.Contains(t, slow.TestStepResults[0].Errors[0].Value, "testcase timeout of 50ms exceeded")
+	require.True(t, slow.TestStepResults[1].Finally)
+	require.Equal(t, StatusPass, slow.TestStepResults[1].Status)
+	require.Equal(t, StatusPass, ts.TestCases[1].Status)
+
+	ts = testSuiteByName(t, v, "testsuite timeout")
+	require.Equal(t, StatusFail, ts.Status)
+	require.Equal(t, StatusFail, ts.TestCases[0].Status)
+	require.Contains(t, ts.TestCases[0].TestStepResults[0].Errors[0].Value, "testsuite timeout of 100ms exceeded")
+	require.Equal(t, StatusSkip, ts.TestCases[1].Status)
+	require.Equal(t, "===== testsuite timeout of 100ms exceeded =====", ts.TestCases[1].Skipped[0].Value)
+	require.Equal(t, StatusPass, ts.Teardown.Status)
+}
+
+func TestProcessRunTimeout(t *testing.T) {
+	v := newTestVenom(t, map[string]Executor{"sleep": sleepExecutor})
+	v.Timeout = 100 * time.Millisecond
+	runTestSuites(t, v, `name: first
+testcases:
+- name: slow
+  steps:
+  - type: sleep
+    duration: 10s
+`, `name: second
+testcases:
+- name: slow
+  steps:
+  - type: sleep
+    duration: 10s
+`)
+
+	// the testsuites are run in any order: the first one times out, the second one is not run
+	require.Equal(t, StatusFail, v.Tests.Status)
+	run, notRun := &v.Tests.TestSuites[0], &v.Tests.TestSuites[1]
+	require.Equal(t, StatusFail, run.Status)
+	require.Contains(t, run.TestCases[0].TestStepResults[0].Errors[0].Value, "run timeout of 100ms exceeded")
+	require.Equal(t, StatusSkip, notRun.Status)
+	require.Equal(t, "===== run timeout of 100ms exceeded =====", notRun.TestCases[0].Skipped[0].Value)
+}
+
+func TestProcessInterrupted(t *testing.T) {
+	v := newTestVenom(t, map[string]Executor{"sleep": sleepExecutor, "echo": echoExecutor})
+	dir := writeTestSuites(t, `name: suite
+testcases:
+- name: interrupted
+  steps:
+  - type: sleep
+    duration: 10s
+  finally:
+  - type: echo
+    value: cleanup
+- name: not run
+  steps:
+  - type: echo
+    value: not run
+teardown:
+- type: echo
+  value: cleanup
+`)
+	require.NoError(t, v.Parse(context.Background(), []string{dir}))
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(50*time.Millisecond, func() { cancel(InterruptedError{Signal: os.Interrupt}) })
+	require.NoError(t, v.Process(ctx, []string{dir}))
+
+	require.Equal(t, StatusFail, v.Tests.Status)
+	ts := v.Tests.TestSuites[0]
+	interrupted := ts.TestCases[0]
+	require.Equal(t, StatusFail, interrupted.Status)
+	require.True(t, interrupted.TestStepResults[0].Interrupted)
+	require.Contains(t, interrupted.TestStepResults[0].Errors[0].Value, "interrupted by signal interrupt")
+	require.Equal(t, StatusPass, interrupted.TestStepResults[1].Status)
+	require.Equal(t, StatusSkip, ts.TestCases[1].Status)
+	require.Equal(t, "===== interrupted by signal interrupt =====", ts.TestCases[1].Skipped[0].Value)
+	require.Equal(t, StatusPass, ts.Teardown.Status)
+}
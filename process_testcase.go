@@ -33,7 +33,7 @@ func (v *Venom) parseTestCase(_ *TestSuite, tc *TestCase) ([]string, []string, e
 	for i := range dvars {
 		dvars[i] = escapeQuotes(dvars[i])
 	}
-	for _, rawStep := range tc.RawTestSteps {
+	for _, rawStep := range tc.allRawTestSteps() {
 		content, err := interpolate.Do(string(rawStep), dvars)
 		if err != nil {
 			return nil, nil, err
@@ -66,11 +66,11 @@ func (v *Venom) parseTestCase(_ *TestSuite, tc *TestCase) ([]string, []string, e
 				if !found {
 					extractedVars = append(extractedVars, k)
 				}
-				extractedVars = append(extractedVars, tc.Name+"."+k)
+				extractedVars = append(extractedVars, tc.computedVarsPrefix()+"."+k)
 				if strings.HasSuffix(k, "__type__") && dumpE[k] == "Map" {
 					// go-dump doesn't dump the map name, here is a workaround
 					k = strings.TrimSuffix(k, "__type__")
-					extractedVars = append(extractedVars, tc.Name+"."+k)
+					extractedVars = append(extractedVars, tc.computedVarsPrefix()+"."+k)
 				}
 			}
 		}
@@ -82,7 +82,7 @@ func (v *Venom) parseTestCase(_ *TestSuite, tc *TestCase) ([]string, []string, e
 
 		for k, v := range dumpE {
 			if strings.HasPrefix(k, "vars.") {
-				s := tc.Name + "." + strings.Split(k[5:], ".")[0]
+				s := tc.computedVarsPrefix() + "." + strings.Split(k[5:], ".")[0]
 				extractedVars = append(extractedVars, s)
 				continue
 			}
@@ -90,7 +90,7 @@ func (v *Venom) parseTestCase(_ *TestSuite, tc *TestCase) ([]string, []string, e
 				continue
 			}
 			if strings.HasPrefix(k, "extracts.") {
-				s := tc.Name + "." + strings.Split(k[9:], ".")[0]
+				s := tc.computedVarsPrefix() + "." + strings.Split(k[9:], ".")[0]
 				extractedVars = append(extractedVars, s)
 				continue
 			}
@@ -135,12 +135,18 @@ func (v *Venom) parseTestCase(_ *TestSuite, tc *TestCase) ([]string, []string, e
 
 func (v *Venom) runTestCase(ctx context.Context, ts *TestSuite, tc *TestCase) {
 	ctx = context.WithValue(ctx, ContextKey("testcase"), tc.Name)
+	if tc.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, time.Duration(tc.Timeout), timeoutError{scope: "testcase", timeout: time.Duration(tc.Timeout)})
+		defer cancel()
+	}
 
 	tc.TestSuiteVars = ts.Vars.Clone()
 	tc.Vars = ts.Vars.Clone()
 	tc.Vars.Add("venom.testcase", tc.Name)
+	tc.addRangeVars()
 	tc.Vars.AddAll(ts.ComputedVars)
-	tc.Vars.Add("venom.testcase.totalSteps", len(tc.RawTestSteps))
+	tc.Vars.Add("venom.testcase.totalSteps", len(tc.RawTestSteps)+len(tc.RawFinallySteps))
 	tc.computedVars = H{}
 
 	ctx = v.processSecrets(ctx, ts, tc)
@@ -182,32 +188,73 @@ func (v *Venom) runTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepRe
 		return
 	}
 
-	knowExecutors := map[string]struct{}{}
-	previousStepVars := H{}
+	run := &testStepsRun{
+		knowExecutors:    map[string]struct{}{},
+		previousStepVars: H{},
+	}
+	defer func() {
+		// executors are torn down in the reverse order of their setup
+		for i := len(run.teardowns) - 1; i >= 0; i-- {
+			run.teardowns[i]()
+		}
+	}()
+
+	ctx = v.runRawTestSteps(ctx, tc, tsIn, run, tc.RawTestSteps, 0, false)
+	if len(tc.RawFinallySteps) > 0 {
+		Info(ctx, "Running finally steps")
+		// the finally steps are run even if the testcase has been cancelled
+		v.runRawTestSteps(context.WithoutCancel(ctx), tc, tsIn, run, tc.RawFinallySteps, len(tc.RawTestSteps), true)
+	}
+}
+
+// testStepsRun contains the state shared by the steps of a testcase and its finally steps
+type testStepsRun struct {
+	knowExecutors    map[string]struct{}
+	previousStepVars H
+	teardowns        []func()
+	// cancelled is true when a step has failed because the testcase has been cancelled
+	cancelled bool
+}
+
+// runRawTestSteps runs the steps of a testcase, numbered from firstStepNumber+1
+func (v *Venom) runRawTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepResult, run *testStepsRun, rawSteps []json.RawMessage, firstStepNumber int, finally bool) context.Context {
 	fromUserExecutor := tsIn != nil
 
 loopRawTestSteps:
-	for stepIndex, rawStep := range tc.RawTestSteps {
+	for stepIndex, rawStep := range rawSteps {
 		stepVars := tc.Vars.Clone()
-		stepVars.AddAll(previousStepVars)
-		stepVars.AddAllWithPrefix(tc.Name, tc.computedVars)
+		stepVars.AddAll(run.previousStepVars)
+		stepVars.AddAllWithPrefix(tc.computedVarsPrefix(), tc.computedVars)
 
 		// Use stepNumber as a 1-based index
-		stepNumber := stepIndex + 1
+		stepNumber := firstStepNumber + stepIndex + 1
 		stepVars.Add("venom.teststep.number", stepNumber)
 
+		if err := context.Cause(ctx); err != nil {
+			// the testcase fails if it has been cancelled between two steps
+			if !run.cancelled {
+				tc.TestStepResults = append(tc.TestStepResults, TestStepResult{Number: stepNumber, Status: StatusFail, Finally: finally, Position: tc.stepPosition(stepNumber)})
+				tsResult := &tc.TestStepResults[len(tc.TestStepResults)-1]
+				tsResult.appendFailure(*newFailure(ctx, *tc, stepNumber, 0, "", err))
+				run.cancelled = true
+			}
+			Warn(ctx, "skipping remaining steps: %v", err)
+			return ctx
+		}
+
 		ranged, err := parseRanged(ctx, rawStep, stepVars)
 		if err != nil {
 			Error(ctx, "unable to parse \"range\" attribute: %v", err)
-			testStepResult := TestStepResult{}
+			testStepResult := TestStepResult{Position: tc.stepPosition(stepNumber)}
 			testStepResult.appendError(err)
 			tc.TestStepResults = append(tc.TestStepResults, testStepResult)
-			return
+			return ctx
 		}
 
 		for rangedIndex, rangedData := range ranged.Items {
-			tc.TestStepResults = append(tc.TestStepResults, TestStepResult{})
+			tc.TestStepResults = append(tc.TestStepResults, TestStepResult{Position: tc.stepPosition(stepNumber)})
 			tsResult := &tc.TestStepResults[len(tc.TestStepResults)-1]
+			tsResult.Finally = finally
 
 			if ranged.Enabled {
 				Debug(ctx, "processing range index: %d", rangedIndex)
@@ -220,7 +267,7 @@ loopRawTestSteps:
 			if err != nil {
 				Error(ctx, "unable to dump testcase vars: %v", err)
 				tsResult.appendError(err)
-				return
+				return ctx
 			}
 
 			for k, v := range vars {
@@ -228,7 +275,7 @@ loopRawTestSteps:
 				if err != nil {
 					tsResult.appendError(err)
 					Error(ctx, "unable to interpolate variable %q: %v", k, err)
-					return
+					return ctx
 				}
 				vars[k] = content
 			}
@@ -251,7 +298,7 @@ loopRawTestSteps:
 				if err != nil {
 					tsResult.appendError(err)
 					Error(ctx, "unable to interpolate step: %v", err)
-					return
+					return ctx
 				}
 				if !strings.Contains(content, "{{") {
 					break
@@ -302,8 +349,9 @@ loopRawTestSteps:
 				break loopRawTestSteps
 			}
 
-			if e != nil {
-				_, known := knowExecutors[e.Name()]
+			// the executors are not set up in dry-run mode, as they are not run
+			if e != nil && !v.DryRun {
+				_, known := run.knowExecutors[e.Name()]
 				if !known {
 					ctx, err = e.Setup(ctx, tc.Vars)
 					if err != nil {
@@ -311,13 +359,15 @@ loopRawTestSteps:
 						Error(ctx, "unable to setup executor: %v", err)
 						break
 					}
-					knowExecutors[e.Name()] = struct{}{}
-					defer func(ctx context.Context) {
-						if err := e.TearDown(ctx); err != nil {
+					run.knowExecutors[e.Name()] = struct{}{}
+					setupCtx := ctx
+					run.teardowns = append(run.teardowns, func() {
+						// the executors are torn down even if the testcase has been cancelled
+						if err := e.TearDown(context.WithoutCancel(setupCtx)); err != nil {
 							tsResult.appendError(err)
-							Error(ctx, "unable to teardown executor: %v", err)
+							Error(setupCtx, "unable to teardown executor: %v", err)
 						}
-					}(ctx)
+					})
 				}
 			}
 			v.setTestStepName(tsResult, e, step, &ranged, &rangedData, rangedIndex)
@@ -327,7 +377,7 @@ loopRawTestSteps:
 
 			// ##### RUN Test Step Here
 			skipVars := tc.Vars.Clone()
-			skipVars.AddAllWithPrefix(tc.Name, previousStepVars)
+			skipVars.AddAllWithPrefix(tc.computedVarsPrefix(), run.previousStepVars)
 			skip, err := parseSkip(ctx, tc, tsResult, rawStep, stepNumber, skipVars)
 			if err != nil {
 				tsResult.appendError(err)
@@ -337,15 +387,28 @@ loopRawTestSteps:
 			} else {
 				tsResult.Start = time.Now()
 				tsResult.Status = StatusRun
-				v.RunTestStep(ctx, e, tc, tsResult, stepNumber, rangedIndex, step)
+				if !tc.IsExecutor {
+					v.notify(func(h Hooks) { h.OnStepStart(ctx, tc, tsResult) })
+				}
+				if v.DryRun {
+					v.dryRunTestStep(ctx, e, tc, tsResult, tsIn, stepNumber, rangedIndex, rawStep, step)
+				} else {
+					v.RunTestStep(ctx, e, tc, tsResult, stepNumber, rangedIndex, step)
+				}
 				if len(tsResult.Errors) > 0 || !tsResult.AssertionsApplied.OK {
 					tsResult.Status = StatusFail
+					run.cancelled = run.cancelled || ctx.Err() != nil
+					var interrupted InterruptedError
+					tsResult.Interrupted = errors.As(context.Cause(ctx), &interrupted)
 				} else {
 					tsResult.Status = StatusPass
 				}
 
 				tsResult.End = time.Now()
 				tsResult.Duration = tsResult.End.Sub(tsResult.Start).Seconds()
+				if !tc.IsExecutor {
+					v.notify(func(h Hooks) { h.OnStepEnd(ctx, tc, tsResult) })
+				}
 
 				tc.testSteps = append(tc.testSteps, step)
 			}
@@ -374,7 +437,7 @@ loopRawTestSteps:
 					failure := newFailure(ctx, *tc, stepNumber, rangedIndex, "", errors.New("At least one required assertion failed, skipping remaining steps"))
 					tsResult.appendFailure(*failure)
 					v.printTestStepResult(tc, tsResult, tsIn, stepNumber, true)
-					return
+					return ctx
 				}
 				v.printTestStepResult(tc, tsResult, tsIn, stepNumber, false)
 				continue
@@ -383,7 +446,7 @@ loopRawTestSteps:
 			allVars := tc.Vars.Clone()
 			allVars.AddAll(tsResult.ComputedVars.Clone())
 
-			assign, _, errAssignment := processVariableAssignments(ctx, tc.Name, allVars, rawStep)
+			assign, _, errAssignment := processVariableAssignments(ctx, tc.computedVarsPrefix(), allVars, rawStep)
 			if errAssignment != nil {
 				tsResult.appendError(errAssignment)
 				Error(ctx, "unable to process variable assignments: %v", errAssignment)
@@ -396,9 +459,10 @@ loopRawTestSteps:
 			}
 
 			tc.computedVars.AddAll(assign)
-			previousStepVars.AddAll(assign)
+			run.previousStepVars.AddAll(assign)
 		}
 	}
+	return ctx
 }
 
 // Set test step name (defaults to executor name, excepted if it got a "name" attribute. in range, also print key)
@@ -427,7 +491,12 @@ func (v *Venom) printTestStepResult(tc *TestCase, ts *TestStepResult, tsIn *Test
 		tsIn.ComputedInfo = append(tsIn.ComputedInfo, ts.ComputedInfo...)
 	} else if v.Verbose >= 1 {
 		if len(ts.Errors) > 0 {
-			v.Println(" %s", Red(StatusFail))
+			if ts.Interrupted {
+				v.Println(" %s %s", Red(StatusFail), Gray("(interrupted)"))
+			} else {
+				v.Println(" %s", Red(StatusFail))
+			}
+			v.printUntilAttempts(ts)
 			for _, i := range ts.ComputedInfo {
 				v.Println(" \t\t  %s %s", Cyan("[info]"), Cyan(i))
 			}
@@ -435,7 +504,11 @@ func (v *Venom) printTestStepResult(tc *TestCase, ts *TestStepResult, tsIn *Test
 				v.Println(" \t\t  %s", Yellow(f.Value))
 			}
 			if mustAssertionFailed {
-				skipped := len(tc.RawTestSteps) - stepNumber
+				lastStepNumber := len(tc.RawTestSteps)
+				if stepNumber > lastStepNumber {
+					lastStepNumber += len(tc.RawFinallySteps)
+				}
+				skipped := lastStepNumber - stepNumber
 				if skipped == 1 {
 					v.Println(" \t\t  %s", Gray(fmt.Sprintf("%d other step was skipped", skipped)))
 				} else {
@@ -450,6 +523,7 @@ func (v *Venom) printTestStepResult(tc *TestCase, ts *TestStepResult, tsIn *Test
 			} else {
 				v.Println(" %s (after %d attempts)", Green(StatusPass), ts.Retries)
 			}
+			v.printUntilAttempts(ts)
 			for _, i := range ts.ComputedInfo {
 				v.Println(" \t\t  %s %s", Cyan("[info]"), Cyan(i))
 			}
@@ -457,6 +531,17 @@ func (v *Venom) printTestStepResult(tc *TestCase, ts *TestStepResult, tsIn *Test
 	}
 }
 
+// printUntilAttempts prints the attempts of a step polling its until condition
+func (v *Venom) printUntilAttempts(ts *TestStepResult) {
+	for _, attempt := range ts.UntilAttempts {
+		if attempt.OK {
+			v.Println(" \t\t  %s", Gray(fmt.Sprintf("[until] attempt %d (%.3fs): condition met", attempt.Number, attempt.Duration)))
+		} else {
+			v.Println(" \t\t  %s", Gray(fmt.Sprintf("[until] attempt %d (%.3fs): %s", attempt.Number, attempt.Duration, strings.Join(attempt.Errors, ", "))))
+		}
+	}
+}
+
 // Parse and format skip conditional
 func parseSkip(ctx context.Context, tc *TestCase, ts *TestStepResult, rawStep []byte, stepNumber int, vars H) (bool, error) {
 	// Load "skip" attribute from step
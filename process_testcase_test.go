@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 )
 
@@ -44,3 +45,58 @@ script: echo 'foo'
 	assert.Nil(t, result)
 	assert.Empty(t, result)
 }
+
+func TestProcessFinally(t *testing.T) {
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	runTestSuites(t, v, `name: suite
+testcases:
+- name: with-finally
+  steps:
+  - type: echo
+    value: user-42
+    vars:
+      user:
+        from: result.value
+  - type: echo
+    value: foo
+    assertions:
+    - result.value MustEqual bar
+  - type: echo
+    value: never-run
+  finally:
+  - type: echo
+    value: "{{.with-finally.user}}"
+    assertions:
+    - result.value ShouldEqual user-42
+  - type: echo
+    value: cleanup
+    assertions:
+    - result.value ShouldEqual failed-cleanup
+- name: totals
+  steps:
+  - type: echo
+    value: "{{.venom.testcase.totalSteps}} {{.venom.testsuite.totalSteps}}"
+    assertions:
+    - result.value ShouldEqual "2 7"
+  finally:
+  - type: echo
+    value: cleanup
+`)
+
+	tc := v.Tests.TestSuites[0].TestCases[0]
+	require.Equal(t, StatusFail, tc.Status)
+	require.Len(t, tc.TestStepResults, 4)
+
+	require.False(t, tc.TestStepResults[1].Finally)
+	require.Equal(t, StatusFail, tc.TestStepResults[1].Status)
+
+	require.True(t, tc.TestStepResults[2].Finally)
+	require.Equal(t, 4, tc.TestStepResults[2].Number)
+	require.Equal(t, StatusPass, tc.TestStepResults[2].Status)
+
+	require.True(t, tc.TestStepResults[3].Finally)
+	require.Equal(t, StatusFail, tc.TestStepResults[3].Status)
+
+	// the finally steps are counted in the total steps of the testcase and of the testsuite
+	require.Equal(t, StatusPass, v.Tests.TestSuites[0].TestCases[1].Status)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gosimple/slug"
@@ -27,18 +30,30 @@ func (v *Venom) RunTestStep(ctx context.Context, e ExecutorRunner, tc *TestCase,
 
 	for tsResult.Retries = 0; tsResult.Retries <= e.Retry() && !assertRes.OK; tsResult.Retries++ {
 		if tsResult.Retries >= 1 && !assertRes.OK {
-			Debug(ctx, "Sleep %d, it's %d attempt", e.Delay(), tsResult.Retries)
-			time.Sleep(time.Duration(e.Delay()) * time.Second)
+			delay := retryDelay(e, tsResult.Retries)
+			Debug(ctx, "Sleep %s, it's %d attempt", delay, tsResult.Retries)
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
 		}
 
 		var err error
-		result, err = v.runTestStepExecutor(ctx, e, tc, tsResult, step)
+		if e.Until() != nil {
+			result, err = v.pollTestStepExecutor(ctx, e, tc, tsResult, stepNumber, rangedIndex, step)
+		} else {
+			result, err = v.runTestStepExecutor(ctx, e, tc, tsResult, step)
+		}
 		if err != nil {
 			// we save the failure only if it's the last attempt
-			if tsResult.Retries == e.Retry() {
+			retry := slices.Contains(e.RetryOn(), retryOnError) && ctx.Err() == nil
+			if tsResult.Retries == e.Retry() || !retry {
 				failure := newFailure(ctx, *tc, stepNumber, rangedIndex, "", err)
 				tsResult.appendFailure(*failure)
 			}
+			if !retry {
+				break
+			}
 			continue
 		}
 
@@ -75,37 +90,30 @@ func (v *Venom) RunTestStep(ctx context.Context, e ExecutorRunner, tc *TestCase,
 			if info == "" {
 				continue
 			}
-			filename := StringVarFromCtx(ctx, "venom.testsuite.filename")
-			lineNumber := findLineNumber(filename, tc.originalName, stepNumber, i, ninfo+1)
-			if lineNumber > 0 {
-				info += fmt.Sprintf(" (%s:%d)", filename, lineNumber)
-			} else if tc.IsExecutor {
-				filename = StringVarFromCtx(ctx, "venom.executor.filename")
-				originalName := StringVarFromCtx(ctx, "venom.executor.name")
-				lineNumber = findLineNumber(filename, originalName, stepNumber, i, ninfo+1)
-				if lineNumber > 0 {
-					info += fmt.Sprintf(" (%s:%d)", filename, lineNumber)
-				}
+			filename, positions := tc.stepLocation(ctx, stepNumber)
+			if ninfo < len(positions.info) && positions.info[ninfo] != nil {
+				info += fmt.Sprintf(" (%s:%d)", filename, positions.info[ninfo].Line)
 			}
 			Info(ctx, info, nil)
 			tsResult.ComputedInfo = append(tsResult.ComputedInfo, info)
 		}
 
+		_, positions := tc.stepLocation(ctx, stepNumber)
 		if result == nil {
 			Debug(ctx, "empty testcase, applying assertions on variables: %v", AllVarsFromCtx(ctx))
-			assertRes = applyAssertions(ctx, AllVarsFromCtx(ctx), *tc, stepNumber, rangedIndex, step, nil)
+			assertRes = applyAssertions(ctx, AllVarsFromCtx(ctx), *tc, stepNumber, rangedIndex, step, nil, positions.assertions)
 		} else {
 			if h, ok := e.(executorWithDefaultAssertions); ok {
-				assertRes = applyAssertions(ctx, result, *tc, stepNumber, rangedIndex, step, h.GetDefaultAssertions())
+				assertRes = applyAssertions(ctx, result, *tc, stepNumber, rangedIndex, step, h.GetDefaultAssertions(), positions.assertions)
 			} else {
-				assertRes = applyAssertions(ctx, result, *tc, stepNumber, rangedIndex, step, nil)
+				assertRes = applyAssertions(ctx, result, *tc, stepNumber, rangedIndex, step, nil, positions.assertions)
 			}
 		}
 
 		tsResult.AssertionsApplied = assertRes
 		tsResult.ComputedVars.AddAll(H(mapResult))
 
-		if assertRes.OK {
+		if assertRes.OK || !slices.Contains(e.RetryOn(), retryOnAssertion) || ctx.Err() != nil {
 			break
 		}
 		failures, err := testConditionalStatement(ctx, tc, e.RetryIf(), tsResult.ComputedVars, "")
@@ -132,42 +140,128 @@ func (v *Venom) RunTestStep(ctx context.Context, e ExecutorRunner, tc *TestCase,
 	tsResult.Systemout += assertRes.systemout + "\n"
 }
 
+// maxRetryDelay is the maximum delay between two retries reached by the exponential backoff
+const maxRetryDelay = 5 * time.Minute
+
+// retryDelay returns the delay before the given retry of a step
+func retryDelay(e ExecutorRunner, retry int) time.Duration {
+	delay := e.DelayDuration()
+	if e.RetryBackoff() == "exponential" {
+		// the delay is doubled at each retry, up to maxRetryDelay unless the delay of the step is longer
+		backoff := delay
+		for i := 1; i < retry && backoff < maxRetryDelay; i++ {
+			backoff *= 2
+		}
+		delay = max(delay, min(backoff, maxRetryDelay))
+	}
+	if e.RetryJitter() > 0 {
+		delay += rand.N(e.RetryJitter())
+	}
+	return delay
+}
+
+// pollTestStepExecutor runs the executor until the until condition of the step is met, or until its max duration is reached
+func (v *Venom) pollTestStepExecutor(ctx context.Context, e ExecutorRunner, tc *TestCase, tsResult *TestStepResult, stepNumber int, rangedIndex int, step TestStep) (interface{}, error) {
+	until := e.Until()
+	condition := TestStep{"assertions": until.Assertions}
+	interval := time.Duration(until.Interval)
+	deadline := time.Now().Add(time.Duration(until.MaxDuration))
+
+	for attempts := 1; ; attempts++ {
+		attempt := UntilAttempt{Number: len(tsResult.UntilAttempts) + 1, Start: time.Now()}
+		result, err := v.runTestStepExecutor(ctx, e, tc, tsResult, step)
+		if err != nil {
+			attempt.Errors = append(attempt.Errors, err.Error())
+		} else {
+			var assertRes AssertionsApplied
+			_, positions := tc.stepLocation(ctx, stepNumber)
+			if result == nil {
+				assertRes = applyAssertions(ctx, AllVarsFromCtx(ctx), *tc, stepNumber, rangedIndex, condition, nil, positions.until)
+			} else {
+				assertRes = applyAssertions(ctx, result, *tc, stepNumber, rangedIndex, condition, nil, positions.until)
+			}
+			attempt.OK = assertRes.OK
+			for _, f := range assertRes.errors {
+				attempt.Errors = append(attempt.Errors, f.Value)
+			}
+		}
+		attempt.Duration = time.Since(attempt.Start).Seconds()
+		tsResult.UntilAttempts = append(tsResult.UntilAttempts, attempt)
+
+		if attempt.OK {
+			Debug(ctx, "until condition met at attempt %d", attempts)
+			return result, nil
+		}
+		Debug(ctx, "until condition not met at attempt %d: %v", attempts, attempt.Errors)
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, fmt.Errorf("until condition not met after %d attempts in %s: %s", attempts, until.MaxDuration, strings.Join(attempt.Errors, ", "))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		case <-time.After(min(interval, remaining)):
+		}
+		if until.Backoff == "exponential" {
+			interval *= 2
+		}
+	}
+}
+
 func (v *Venom) runTestStepExecutor(ctx context.Context, e ExecutorRunner, tc *TestCase, ts *TestStepResult, step TestStep) (interface{}, error) {
 	ctx = context.WithValue(ctx, ContextKey("executor"), e.Name())
 
-	if e.Timeout() == 0 {
+	ctxTimeout := ctx
+	if e.TimeoutDuration() > 0 {
+		var cancel context.CancelFunc
+		ctxTimeout, cancel = context.WithTimeout(ctx, e.TimeoutDuration())
+		defer cancel()
+	}
+
+	run := func(ctx context.Context) (interface{}, error) {
 		if e.Type() == "user" {
 			return v.RunUserExecutor(ctx, e, tc, ts, step)
 		}
 		return e.Run(ctx, step)
 	}
+	if ctxTimeout.Done() == nil {
+		// the step can neither time out nor be cancelled
+		return run(ctx)
+	}
 
-	ctxTimeout, cancel := context.WithTimeout(ctx, time.Duration(e.Timeout())*time.Second)
-	defer cancel()
-
-	ch := make(chan interface{})
-	cherr := make(chan error)
-	go func(e ExecutorRunner, step TestStep) {
-		var err error
-		var result interface{}
-		if e.Type() == "user" {
-			result, err = v.RunUserExecutor(ctx, e, tc, ts, step)
-		} else {
-			result, err = e.Run(ctx, step)
+	// interrupted returns the reason why the executor has been interrupted, if any
+	interrupted := func() error {
+		if err := context.Cause(ctx); err != nil {
+			return err
 		}
+		if ctxTimeout.Err() != nil {
+			return fmt.Errorf("Timeout after %s", e.TimeoutDuration())
+		}
+		return nil
+	}
+
+	ch := make(chan interface{}, 1)
+	cherr := make(chan error, 1)
+	go func() {
+		result, err := run(ctxTimeout)
 		if err != nil {
 			cherr <- err
 		} else {
 			ch <- result
 		}
-	}(e, step)
+	}()
 
 	select {
 	case err := <-cherr:
+		// the executor may return an error because its context has been cancelled
+		if errInterrupted := interrupted(); errInterrupted != nil {
+			return nil, errInterrupted
+		}
 		return nil, err
 	case result := <-ch:
 		return result, nil
 	case <-ctxTimeout.Done():
-		return nil, fmt.Errorf("Timeout after %d second(s)", e.Timeout())
+		return nil, interrupted()
 	}
 }
@@ -0,0 +1,154 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessUntil(t *testing.T) {
+	var count int32
+	counter := funcExecutor(func(ctx context.Context, step TestStep) (interface{}, error) {
+		return map[string]interface{}{"result": map[string]interface{}{"count": float64(atomic.AddInt32(&count, 1))}}, nil
+	})
+	v := newTestVenom(t, map[string]Executor{"counter": counter})
+	runTestSuites(t, v, `name: suite
+testcases:
+- name: met
+  steps:
+  - type: counter
+    until:
+      assertions:
+      - result.count ShouldBeGreaterThanOrEqualTo 3
+      interval: 10ms
+      max_duration: 5s
+    assertions:
+    - result.count ShouldEqual 3
+- name: not met
+  steps:
+  - type: counter
+    until:
+      assertions:
+      - result.count ShouldBeLessThan 0
+      interval: 20ms
+      max_duration: 100ms
+      backoff: exponential
+`)
+
+	ts := v.Tests.TestSuites[0]
+	met := ts.TestCases[0]
+	require.Equal(t, StatusPass, met.Status)
+	attempts := met.TestStepResults[0].UntilAttempts
+	require.Len(t, attempts, 3)
+	require.False(t, attempts[0].OK)
+	require.NotEmpty(t, attempts[0].Errors)
+	require.True(t, attempts[2].OK)
+
+	notMet := ts.TestCases[1]
+	require.Equal(t, StatusFail, notMet.Status)
+	// attempts at 0, 20ms, 60ms and 100ms, there would be 6 attempts without the backoff
+	nAttempts := len(notMet.TestStepResults[0].UntilAttempts)
+	require.True(t, nAttempts >= 2 && nAttempts <= 4, "%d attempts", nAttempts)
+	require.Contains(t, notMet.TestStepResults[0].Errors[0].Value, fmt.Sprintf("until condition not met after %d attempts in 100ms", nAttempts))
+
+	dir := writeTestSuites(t, `name: invalid
+testcases:
+- name: invalid
+  steps:
+  - type: counter
+    until:
+      assertions:
+      - result.count ShouldBeLessThan 0
+`)
+	err := newTestVenom(t, map[string]Executor{"counter": counter}).Parse(context.Background(), []string{dir})
+	require.ErrorContains(t, err, `attribute "until" has no max_duration`)
+}
+
+func TestProcessRetry(t *testing.T) {
+	calls := map[string]int{}
+	var mutex sync.Mutex
+	flaky := funcExecutor(func(ctx context.Context, step TestStep) (interface{}, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		name := step["value"].(string)
+		calls[name]++
+		if step["error"] == true {
+			return nil, fmt.Errorf("error %d", calls[name])
+		}
+		return map[string]interface{}{"result": map[string]interface{}{"calls": float64(calls[name])}}, nil
+	})
+	sleep := funcExecutor(func(ctx context.Context, step TestStep) (interface{}, error) {
+		time.Sleep(time.Second)
+		return nil, nil
+	})
+	v := newTestVenom(t, map[string]Executor{"flaky": flaky, "sleep": sleep})
+	runTestSuites(t, v, `name: suite
+testcases:
+- name: retried
+  steps:
+  - type: flaky
+    value: retried
+    retry: 3
+    delay: 10ms
+    retry_backoff: exponential
+    retry_jitter: 5ms
+    assertions:
+    - result.calls ShouldEqual 3
+- name: not retried on error
+  steps:
+  - type: flaky
+    value: not retried on error
+    error: true
+    retry: 3
+    retry_on: [assertion]
+- name: not retried on assertion
+  steps:
+  - type: flaky
+    value: not retried on assertion
+    retry: 3
+    retry_on: error
+    assertions:
+    - result.calls ShouldEqual 3
+- name: timeout
+  steps:
+  - type: sleep
+    timeout: 50ms
+`)
+
+	ts := v.Tests.TestSuites[0]
+	require.Equal(t, StatusPass, ts.TestCases[0].Status)
+	require.Equal(t, 3, calls["retried"])
+	require.Equal(t, StatusFail, ts.TestCases[1].Status)
+	require.Equal(t, 1, calls["not retried on error"])
+	require.Equal(t, StatusFail, ts.TestCases[2].Status)
+	require.Equal(t, 1, calls["not retried on assertion"])
+	require.Equal(t, StatusFail, ts.TestCases[3].Status)
+	require.Contains(t, ts.TestCases[3].TestStepResults[0].Errors[0].Value, "Timeout after 50ms")
+}
+
+func TestRetryDelay(t *testing.T) {
+	e := newExecutorRunner(nil, "", "builtin", stepSettings{delay: 100 * time.Millisecond})
+	require.Equal(t, 100*time.Millisecond, retryDelay(e, 1))
+	require.Equal(t, 100*time.Millisecond, retryDelay(e, 3))
+
+	e = newExecutorRunner(nil, "", "builtin", stepSettings{delay: 100 * time.Millisecond, retryBackoff: "exponential"})
+	require.Equal(t, 100*time.Millisecond, retryDelay(e, 1))
+	require.Equal(t, 400*time.Millisecond, retryDelay(e, 3))
+	// the backoff is bounded, without overflowing
+	for _, retry := range []int{20, 31, 64, 1000} {
+		require.Equal(t, maxRetryDelay, retryDelay(e, retry), retry)
+	}
+	e = newExecutorRunner(nil, "", "builtin", stepSettings{delay: 10 * time.Minute, retryBackoff: "exponential"})
+	require.Equal(t, 10*time.Minute, retryDelay(e, 40))
+
+	e = newExecutorRunner(nil, "", "builtin", stepSettings{delay: 100 * time.Millisecond, retryJitter: 50 * time.Millisecond})
+	for i := 0; i < 10; i++ {
+		delay := retryDelay(e, 1)
+		require.True(t, delay >= 100*time.Millisecond && delay < 150*time.Millisecond, delay)
+	}
+}
@@ -2,10 +2,13 @@ package venom
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime/pprof"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/gosimple/slug"
@@ -58,7 +61,7 @@ func (v *Venom) runTestSuite(ctx context.Context, ts *TestSuite) error {
 
 	totalSteps := 0
 	for _, tc := range ts.TestCases {
-		totalSteps += len(tc.RawTestSteps)
+		totalSteps += len(tc.RawTestSteps) + len(tc.RawFinallySteps)
 	}
 
 	ts.Vars.Add(("venom.testsuite.totalSteps"), totalSteps)
@@ -67,140 +70,175 @@ func (v *Venom) runTestSuite(ctx context.Context, ts *TestSuite) error {
 	for _, v := range ts.Secrets {
 		Info(ctx, "secret  %+v", v)
 	}
+	// a testsuite running when it is cancelled fails, a testsuite not started yet is skipped
+	cancelledAtStart := ctx.Err() != nil
+	if ts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, time.Duration(ts.Timeout), timeoutError{scope: "testsuite", timeout: time.Duration(ts.Timeout)})
+		defer cancel()
+	}
+
+	v.notify(func(h Hooks) { h.OnSuiteStart(ctx, ts) })
 	// ##### RUN Test Cases Here
 	v.runTestCases(ctx, ts)
 
-	var isFailed bool
-	var nSkip int
-	for _, tc := range ts.TestCases {
-		if tc.Status == StatusFail {
-			isFailed = true
-			ts.NbTestcasesFail++
-		} else if tc.Status == StatusSkip {
-			nSkip++
-			ts.NbTestcasesSkip++
-		} else if tc.Status == StatusPass {
-			ts.NbTestcasesPass++
-		}
-	}
-
-	if isFailed {
+	ts.computeStatus()
+	if !cancelledAtStart && ctx.Err() != nil {
 		ts.Status = StatusFail
-		v.Tests.NbTestsuitesFail++
-	} else if nSkip > 0 && nSkip == len(ts.TestCases) {
-		ts.Status = StatusSkip
-		v.Tests.NbTestsuitesSkip++
-	} else {
-		ts.Status = StatusPass
-		v.Tests.NbTestsuitesPass++
 	}
+	v.notify(func(h Hooks) { h.OnSuiteEnd(ctx, ts) })
 	return nil
 }
 
 func (v *Venom) runTestCases(ctx context.Context, ts *TestSuite) {
-	verboseReport := v.Verbose >= 1
-
 	v.Println(" • %s (%s)", ts.Name, ts.Filepath)
 
-	for i := range ts.TestCases {
-		tc := &ts.TestCases[i]
-		tc.IsEvaluated = true
-		v.Print(" \t• %s", tc.Name)
-		var hasFailure bool
-		var hasRanged bool
-		hasSkipped := len(tc.Skipped) > 0
-		if !hasSkipped {
-			start := time.Now()
-			tc.Start = start
-			ts.Status = StatusRun
-			if verboseReport || hasRanged {
-				v.Print("\n")
+	// the teardown is not run either if the testsuite is cancelled before its start
+	if err := context.Cause(ctx); err != nil && ts.Teardown != nil {
+		ts.Teardown.Skipped = append(ts.Teardown.Skipped, Skipped{Value: fmt.Sprintf("===== %v =====", err)})
+	}
+
+	if ts.Setup != nil {
+		v.processTestCase(ctx, ts, ts.Setup)
+		ts.ComputedVars.AddAllWithPrefix(ts.Setup.Name, ts.Setup.computedVars)
+		if ts.Setup.Status == StatusFail {
+			for i := range ts.TestCases {
+				ts.TestCases[i].Skipped = append(ts.TestCases[i].Skipped, Skipped{Value: "===== setup failed ====="})
 			}
-			// ##### RUN Test Case Here
-			v.runTestCase(ctx, ts, tc)
-			tc.End = time.Now()
-			tc.Duration = tc.End.Sub(tc.Start).Seconds()
 		}
+	}
 
-		skippedSteps := 0
-		for _, testStepResult := range tc.TestStepResults {
-			if testStepResult.RangedEnable {
-				hasRanged = true
-			}
-			if testStepResult.Status == StatusFail {
-				hasFailure = true
-			}
-			if testStepResult.Status == StatusSkip {
-				skippedSteps++
-			}
+	// the teardown is always run, even if the testsuite is stopped on failure or cancelled
+	if ts.Teardown != nil {
+		defer v.processTestCase(context.WithoutCancel(ctx), ts, ts.Teardown)
+	}
+
+	if v.Parallel > 1 && !ts.Serial && ts.hasDependencies() {
+		v.runTestCasesInParallel(ctx, ts)
+		return
+	}
+
+	for _, i := range ts.testCasesOrder() {
+		tc := &ts.TestCases[i]
+		ts.skipOnFailedDependencies(tc)
+		v.processTestCase(ctx, ts, tc)
+
+		if v.StopOnFailure && tc.Status != StatusQuarantined && tc.hasFailedStep() {
+			// break TestSuite
+			ts.skipRemainingTestCases()
+			return
 		}
+		ts.ComputedVars.AddAllWithPrefix(tc.computedVarsPrefix(), tc.computedVars)
+	}
+}
 
-		if hasFailure {
-			tc.Status = StatusFail
-		} else if skippedSteps == len(tc.TestStepResults) {
-			// If all test steps were skipped, consider the test case as skipped
+// hasFailedStep returns true if one of the steps of the testcase has errors
+func (tc *TestCase) hasFailedStep() bool {
+	for _, testStepResult := range tc.TestStepResults {
+		if len(testStepResult.Errors) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// skipRemainingTestCases skips the testcases not run yet, when the testsuite is stopped on failure
+func (ts *TestSuite) skipRemainingTestCases() {
+	for i := range ts.TestCases {
+		tc := &ts.TestCases[i]
+		if tc.Status == "" {
 			tc.Status = StatusSkip
-		} else if tc.Status != StatusSkip {
-			tc.Status = StatusPass
+			tc.IsEvaluated = true
+			tc.Skipped = append(tc.Skipped, Skipped{Value: "===== stop-on-failure: enabled ====="})
 		}
+	}
+}
 
-		// Verbose mode already reported tests status, so just print them when non-verbose
-		indent := ""
+// processTestCase runs a testcase, computes its status and prints its result
+func (v *Venom) processTestCase(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	verboseReport := v.Verbose >= 1
+
+	tc.IsEvaluated = true
+	v.notify(func(h Hooks) { h.OnTestCaseStart(ctx, ts, tc) })
+	v.Print(" \t• %s", tc.Name)
+	// the testcases not started yet when the run is cancelled are skipped
+	if err := context.Cause(ctx); err != nil && len(tc.Skipped) == 0 {
+		tc.Skipped = append(tc.Skipped, Skipped{Value: fmt.Sprintf("===== %v =====", err)})
+	}
+	hasSkipped := len(tc.Skipped) > 0
+	if !hasSkipped {
+		start := time.Now()
+		tc.Start = start
+		ts.Status = StatusRun
 		if verboseReport {
-			indent = "\t  "
-			// If the testcase was entirely skipped, then the verbose mode will not have any output
-			// Print something to inform that the testcase was indeed processed although skipped
-			if len(tc.TestStepResults) == 0 {
-				v.Println("\t\t%s", Gray("• (all steps were skipped)"))
-				continue
-			}
+			v.Print("\n")
+		}
+		// ##### RUN Test Case Here
+		if v.isRepeated(ts, tc) {
+			v.runRepeatedTestCase(ctx, ts, tc)
 		} else {
-			if hasFailure {
-				v.Println(" %s", Red(StatusFail))
-			} else if tc.Status == StatusSkip {
-				v.Println(" %s", Gray(StatusSkip))
-				continue
-			} else {
-				v.Println(" %s", Green(StatusPass))
-			}
+			v.runTestCase(ctx, ts, tc)
 		}
+		tc.End = time.Now()
+		tc.Duration = tc.End.Sub(tc.Start).Seconds()
+	}
 
-		for _, i := range tc.computedVerbose {
-			v.PrintlnIndentedTrace(i, indent)
-		}
+	tc.computeStatus()
+	tc.computeRepeatStatus()
+	tc.computeQuarantineStatus()
+	v.notify(func(h Hooks) { h.OnTestCaseEnd(ctx, ts, tc) })
+	hasFailure := tc.Status == StatusFail || tc.Status == StatusFlaky || tc.hasQuarantinedFailure()
 
-		// Verbose mode already reported failures, so just print them when non-verbose
-		if !verboseReport && hasFailure {
-			for _, testStepResult := range tc.TestStepResults {
-				if len(testStepResult.ComputedInfo) > 0 || len(testStepResult.Errors) > 0 {
-					v.Println(" \t\t• %s", testStepResult.Name)
-					for _, f := range testStepResult.ComputedInfo {
-						v.Println(" \t\t  %s", Cyan(f))
-					}
-					for _, f := range testStepResult.Errors {
-						v.Println(" \t\t  %s", Yellow(f.Value))
-					}
-				}
-			}
+	// Verbose mode already reported tests status, so just print them when non-verbose
+	indent := ""
+	if verboseReport {
+		indent = "\t  "
+		// If the testcase was entirely skipped, then the verbose mode will not have any output
+		// Print something to inform that the testcase was indeed processed although skipped
+		if len(tc.TestStepResults) == 0 {
+			v.Println("\t\t%s", Gray("• (all steps were skipped)"))
+			return
+		}
+	} else {
+		if tc.Status == StatusQuarantined {
+			v.Println(" %s", Cyan(fmt.Sprintf("%s (%s)", StatusQuarantined, tc.Quarantine.Status)))
+		} else if tc.Status == StatusFlaky {
+			v.Println(" %s", Yellow(StatusFlaky))
+		} else if hasFailure {
+			v.Println(" %s", Red(StatusFail))
+		} else if tc.Status == StatusSkip {
+			v.Println(" %s", Gray(StatusSkip))
+			return
+		} else {
+			v.Println(" %s", Green(StatusPass))
 		}
+	}
 
-		if v.StopOnFailure {
-			for _, testStepResult := range tc.TestStepResults {
-				if len(testStepResult.Errors) > 0 {
-					// break TestSuite
-					for i := range ts.TestCases {
-						tc := &ts.TestCases[i]
-						if tc.Status == "" {
-							tc.Status = StatusSkip
-							tc.IsEvaluated = true
-							tc.Skipped = append(tc.Skipped, Skipped{Value: "===== stop-on-failure: enabled ====="})
-						}
-					}
-					return
+	if v.DryRun {
+		v.printDryRunSteps(tc)
+	}
+
+	for _, i := range tc.computedVerbose {
+		v.PrintlnIndentedTrace(i, indent)
+	}
+
+	if tc.Repeat != nil {
+		v.printRepeatAttempts(tc, indent, verboseReport)
+	}
+
+	// Verbose mode already reported failures, so just print them when non-verbose
+	if !verboseReport && hasFailure {
+		for _, testStepResult := range tc.TestStepResults {
+			if len(testStepResult.ComputedInfo) > 0 || len(testStepResult.Errors) > 0 {
+				v.Println(" \t\t• %s", testStepResult.Name)
+				for _, f := range testStepResult.ComputedInfo {
+					v.Println(" \t\t  %s", Cyan(f))
+				}
+				for _, f := range testStepResult.Errors {
+					v.Println(" \t\t  %s", Yellow(f.Value))
 				}
 			}
 		}
-		ts.ComputedVars.AddAllWithPrefix(tc.Name, tc.computedVars)
 	}
 }
 
@@ -213,14 +251,65 @@ func (v *Venom) parseTestSuite(ts *TestSuite) ([]string, []string, error) {
 func (v *Venom) parseTestCases(ts *TestSuite) ([]string, []string, error) {
 	var vars []string
 	var extractsVars []string
+
+	if err := ts.expandRangedTestCases(); err != nil {
+		return nil, nil, err
+	}
+
+	testcases := make([]*TestCase, 0, len(ts.TestCases)+2)
+	if ts.Setup != nil {
+		testcases = append(testcases, ts.Setup)
+	}
 	for i := range ts.TestCases {
-		tc := &ts.TestCases[i]
+		ts.TestCases[i].number = i + 1
+		testcases = append(testcases, &ts.TestCases[i])
+	}
+	if ts.Teardown != nil {
+		ts.Teardown.number = len(ts.TestCases) + 1
+		testcases = append(testcases, ts.Teardown)
+	}
+
+	for _, tc := range testcases {
 		tc.originalName = tc.Name
-		tc.number = i + 1
 		tc.Name = slug.Make(tc.Name)
+		tc.varsPrefix = tc.Name
+		if tc.rangeItem != nil {
+			tc.Name += "[" + tc.rangeItem.label + "]"
+		}
 		tc.Vars = ts.Vars.Clone()
 		tc.Vars.Add("venom.testcase", tc.Name)
+		tc.addRangeVars()
+	}
+	for i := range ts.TestCases {
+		v.quarantineTestCase(ts, &ts.TestCases[i])
+	}
+
+	if err := ts.resolveDependencies(); err != nil {
+		return nil, nil, err
+	}
+
+	var failed map[int]struct{}
+	if v.previous != nil {
+		failed = v.previous.failedTestCases(ts)
+	}
+	matched := v.runFilterTestCases(ts)
+	tagged := v.tagsFilterTestCases(ts)
+	var nSelected int
+	for i := range ts.TestCases {
+		if v.filterTestCase(ts, i, failed, matched, tagged) {
+			nSelected++
+		}
+	}
+	// no need to setup the testsuite if none of its testcases is run
+	if nSelected == 0 && len(ts.TestCases) > 0 {
+		for _, tc := range []*TestCase{ts.Setup, ts.Teardown} {
+			if tc != nil {
+				tc.Skipped = append(tc.Skipped, Skipped{Value: "===== no testcase selected ====="})
+			}
+		}
+	}
 
+	for _, tc := range testcases {
 		if len(tc.Skipped) == 0 {
 			tvars, tExtractedVars, err := v.parseTestCase(ts, tc)
 			if err != nil {
@@ -255,3 +344,57 @@ func (v *Venom) parseTestCases(ts *TestSuite) ([]string, []string, error) {
 
 	return vars, extractsVars, nil
 }
+
+// expandRangedTestCases replaces each testcase having a range by one testcase per item of the range
+func (ts *TestSuite) expandRangedTestCases() error {
+	var testCases []TestCase
+	for _, tc := range ts.TestCases {
+		if tc.Range == nil {
+			testCases = append(testCases, tc)
+			continue
+		}
+
+		rawRange, err := json.Marshal(map[string]interface{}{"range": tc.Range})
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse range of testcase %q", tc.Name)
+		}
+		ranged, err := parseRanged(context.Background(), rawRange, ts.Vars)
+		if err != nil {
+			return errors.Wrapf(err, "unable to parse range of testcase %q", tc.Name)
+		}
+		// the items of a map are not ordered
+		sort.SliceStable(ranged.Items, func(i, j int) bool {
+			ki, erri := strconv.Atoi(ranged.Items[i].Key)
+			kj, errj := strconv.Atoi(ranged.Items[j].Key)
+			if erri == nil && errj == nil {
+				return ki < kj
+			}
+			return ranged.Items[i].Key < ranged.Items[j].Key
+		})
+
+		for index, item := range ranged.Items {
+			expanded := tc
+			expanded.rangeItem = &testCaseRangeItem{
+				index: index,
+				key:   item.Key,
+				value: item.Value,
+				label: rangeItemLabel(item),
+			}
+			testCases = append(testCases, expanded)
+		}
+	}
+	ts.TestCases = testCases
+	return nil
+}
+
+// rangeItemLabel returns a readable label for an item of a range: its value if it is a scalar, else its key
+func rangeItemLabel(item RangeData) string {
+	if _, err := strconv.Atoi(item.Key); err != nil {
+		return item.Key
+	}
+	switch value := item.Value.(type) {
+	case string, bool, int, int64, float64, json.Number:
+		return fmt.Sprint(value)
+	}
+	return item.Key
+}
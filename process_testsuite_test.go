@@ -0,0 +1,140 @@
+package venom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessSetupTeardown(t *testing.T) {
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.StopOnFailure = true
+	runTestSuites(t, v, `name: suite
+setup:
+- type: echo
+  value: the-token
+  vars:
+    token:
+      from: result.value
+testcases:
+- name: use-setup-vars
+  steps:
+  - type: echo
+    value: "{{.setup.token}}"
+    assertions:
+    - result.value ShouldEqual the-token
+- name: failing
+  steps:
+  - type: echo
+    value: foo
+    assertions:
+    - result.value ShouldEqual bar
+- name: not-run
+  steps:
+  - type: echo
+    value: foo
+teardown:
+- type: echo
+  value: "{{.setup.token}}"
+  assertions:
+  - result.value ShouldEqual the-token
+`)
+
+	ts := v.Tests.TestSuites[0]
+	require.Equal(t, StatusFail, ts.Status)
+	require.Equal(t, StatusPass, ts.Setup.Status)
+	require.Equal(t, StatusPass, ts.TestCases[0].Status)
+	require.Equal(t, StatusFail, ts.TestCases[1].Status)
+	require.Equal(t, StatusSkip, ts.TestCases[2].Status)
+	require.NotNil(t, ts.Teardown)
+	require.Equal(t, StatusPass, ts.Teardown.Status)
+}
+
+func TestProcessSetupFailure(t *testing.T) {
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	runTestSuites(t, v, `name: suite
+setup:
+- type: echo
+  value: foo
+  assertions:
+  - result.value ShouldEqual bar
+testcases:
+- name: not-run
+  steps:
+  - type: echo
+    value: foo
+teardown:
+- type: echo
+  value: foo
+`)
+
+	ts := v.Tests.TestSuites[0]
+	require.Equal(t, StatusFail, ts.Status)
+	require.Equal(t, StatusFail, ts.Setup.Status)
+	require.Equal(t, StatusSkip, ts.TestCases[0].Status)
+	require.Equal(t, "===== setup failed =====", ts.TestCases[0].Skipped[0].Value)
+	require.Equal(t, StatusPass, ts.Teardown.Status)
+}
+
+func TestProcessTestCaseRange(t *testing.T) {
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.RunFilter = `create-order\[(premium|basic)-user\]|prices|after`
+	runTestSuites(t, v, `name: suite
+vars:
+  prices:
+    b: 2
+    a: 1
+testcases:
+- name: create-order
+  range: [premium-user, basic-user, excluded-user]
+  steps:
+  - type: echo
+    value: "order-{{.index}}-{{.value}}"
+    vars:
+      id:
+        from: result.value
+  - type: echo
+    value: "{{.create-order.id}}"
+    assertions:
+    - result.value ShouldEqual order-0-premium-user
+- name: prices
+  range: '{{.prices}}'
+  steps:
+  - type: echo
+    value: "{{.key}}={{.value}}"
+    assertions:
+    - result.value ShouldEqual {{.key}}={{.value}}
+- name: after
+  depends_on: [create-order]
+  steps:
+  - type: echo
+    value: foo
+`)
+
+	ts := v.Tests.TestSuites[0]
+	var names []string
+	for _, tc := range ts.TestCases {
+		names = append(names, tc.Name)
+	}
+	require.Equal(t, []string{
+		"create-order[premium-user]",
+		"create-order[basic-user]",
+		"create-order[excluded-user]",
+		"prices[a]",
+		"prices[b]",
+		"after",
+	}, names)
+	require.Equal(t, StatusPass, ts.TestCases[0].Status)
+	require.Equal(t, StatusFail, ts.TestCases[1].Status)
+	// not matched by the run filter, but run as a dependency of after
+	require.Equal(t, StatusFail, ts.TestCases[2].Status)
+	require.Equal(t, StatusPass, ts.TestCases[3].Status)
+	require.Equal(t, StatusPass, ts.TestCases[4].Status)
+	require.Equal(t, StatusSkip, ts.TestCases[5].Status)
+	require.Equal(t, "===== dependency create-order[basic-user] failed =====", ts.TestCases[5].Skipped[0].Value)
+
+	data, err := outputXMLFormat(v.Tests, 0)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `name="create-order[premium-user]"`)
+	require.Contains(t, string(data), `name="create-order[basic-user]"`)
+}
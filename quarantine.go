@@ -0,0 +1,108 @@
+package venom
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/rockbears/yaml"
+)
+
+// Quarantine puts the testcases matching its pattern in quarantine: they are run,
+// but their failures do not fail the run
+type Quarantine struct {
+	// Pattern is a regular expression matching "testsuite/testcase", like the run filter
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Owner   string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Ticket  string `json:"ticket,omitempty" yaml:"ticket,omitempty"`
+	Reason  string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// TestCaseQuarantine is the quarantine of a testcase, with the status the testcase would have had
+type TestCaseQuarantine struct {
+	Quarantine
+	Status Status `json:"status" yaml:"status"`
+}
+
+// ReadQuarantineFile reads the quarantine list of a file such as quarantine.yml
+func ReadQuarantineFile(path string) ([]Quarantine, error) {
+	btes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read quarantine file %q", path)
+	}
+	var content struct {
+		Quarantine []Quarantine `json:"quarantine" yaml:"quarantine"`
+	}
+	if err := yaml.Unmarshal(btes, &content); err != nil {
+		return nil, errors.Wrapf(err, "unable to read quarantine file %q", path)
+	}
+	return content.Quarantine, nil
+}
+
+// withoutQuarantineFile removes the quarantine file from the files of the testsuites,
+// it is found by the default glob when it is in the current directory
+func (v *Venom) withoutQuarantineFile(filesPath []string) ([]string, error) {
+	if v.QuarantineFile == "" {
+		return filesPath, nil
+	}
+	quarantineFile, err := filepath.Abs(v.QuarantineFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read quarantine file %q", v.QuarantineFile)
+	}
+	files := make([]string, 0, len(filesPath))
+	for _, f := range filesPath {
+		if abs, err := filepath.Abs(f); err == nil && abs == quarantineFile {
+			continue
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no YAML (*.yml or *.yaml) file found or defined")
+	}
+	return files, nil
+}
+
+// quarantineRule is a Quarantine with its compiled pattern
+type quarantineRule struct {
+	Quarantine
+	re *regexp.Regexp
+}
+
+func compileQuarantine(quarantine []Quarantine) ([]quarantineRule, error) {
+	rules := make([]quarantineRule, 0, len(quarantine))
+	for _, q := range quarantine {
+		re, err := regexp.Compile(q.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid quarantine pattern %q", q.Pattern)
+		}
+		rules = append(rules, quarantineRule{Quarantine: q, re: re})
+	}
+	return rules, nil
+}
+
+// quarantineTestCase puts the testcase in quarantine if it matches one of the quarantine rules
+func (v *Venom) quarantineTestCase(ts *TestSuite, tc *TestCase) {
+	for _, rule := range v.quarantine {
+		if matchRunFilter(rule.re, ts.Name, tc.originalName, tc.rangeItem) {
+			tc.Quarantine = &TestCaseQuarantine{Quarantine: rule.Quarantine}
+			return
+		}
+	}
+}
+
+// computeQuarantineStatus gives the QUARANTINED status to a quarantined testcase which has run,
+// keeping the status it would have had
+func (tc *TestCase) computeQuarantineStatus() {
+	if tc.Quarantine == nil || tc.Status == StatusSkip {
+		return
+	}
+	tc.Quarantine.Status = tc.Status
+	tc.Status = StatusQuarantined
+}
+
+// hasQuarantinedFailure returns true if the testcase is in quarantine and has failed
+func (tc *TestCase) hasQuarantinedFailure() bool {
+	return tc.Status == StatusQuarantined && (tc.Quarantine.Status == StatusFail || tc.Quarantine.Status == StatusFlaky)
+}
@@ -0,0 +1,99 @@
+package venom
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithoutQuarantineFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"quarantine.yml": `quarantine:
+- pattern: "suite/known flaky"
+`,
+		"suite.yml": `name: suite
+testcases:
+- name: known flaky
+  steps:
+  - type: echo
+`,
+	})
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.QuarantineFile = filepath.Join(dir, "quarantine.yml")
+	require.NoError(t, v.Parse(context.Background(), []string{dir}))
+	require.Len(t, v.Tests.TestSuites, 1)
+	require.Equal(t, "suite", v.Tests.TestSuites[0].Name)
+
+	v = newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.QuarantineFile = filepath.Join(dir, "quarantine.yml")
+	require.ErrorContains(t, v.Parse(context.Background(), []string{v.QuarantineFile}), "no YAML (*.yml or *.yaml) file found")
+}
+
+func TestProcessQuarantine(t *testing.T) {
+	suite := `name: suite
+testcases:
+- name: known flaky
+  steps:
+  - type: echo
+    value: foo
+    assertions:
+    - result.value ShouldEqual bar
+- name: after known flaky
+  depends_on: [known flaky]
+  steps:
+  - type: echo
+    value: foo
+- name: quarantined but passing
+  steps:
+  - type: echo
+    value: foo
+- name: ok
+  steps:
+  - type: echo
+    value: foo
+`
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.StopOnFailure = true
+	v.Quarantine = []Quarantine{
+		{Pattern: "suite/known-flaky$", Owner: "alice", Ticket: "QA-42"},
+		{Pattern: "passing"},
+	}
+	runTestSuites(t, v, suite)
+
+	require.Equal(t, StatusPass, v.Tests.Status)
+	ts := v.Tests.TestSuites[0]
+	require.Equal(t, StatusPass, ts.Status)
+	require.Equal(t, 2, ts.NbTestcasesQuarantined)
+
+	flaky := ts.TestCases[0]
+	require.Equal(t, StatusQuarantined, flaky.Status)
+	require.Equal(t, StatusFail, flaky.Quarantine.Status)
+	require.Equal(t, "alice", flaky.Quarantine.Owner)
+	require.Equal(t, "QA-42", flaky.Quarantine.Ticket)
+	require.Equal(t, StatusSkip, ts.TestCases[1].Status)
+	require.Equal(t, "===== dependency known-flaky failed =====", ts.TestCases[1].Skipped[0].Value)
+	require.Equal(t, StatusQuarantined, ts.TestCases[2].Status)
+	require.Equal(t, StatusPass, ts.TestCases[2].Quarantine.Status)
+	require.Equal(t, StatusPass, ts.TestCases[3].Status)
+
+	data, err := outputXMLFormat(v.Tests, 0)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "<error>")
+	require.Contains(t, string(data), `<skipped><![CDATA[quarantined: FAIL`)
+	require.Contains(t, string(data), `<property name="quarantine_ticket" value="QA-42"></property>`)
+
+	dir := writeFiles(t, map[string]string{"quarantine.yml": `quarantine:
+- pattern: "suite/known flaky"
+  owner: alice
+  ticket: QA-42
+`})
+	quarantine, err := ReadQuarantineFile(filepath.Join(dir, "quarantine.yml"))
+	require.NoError(t, err)
+	require.Equal(t, []Quarantine{{Pattern: "suite/known flaky", Owner: "alice", Ticket: "QA-42"}}, quarantine)
+
+	v = newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.Quarantine = []Quarantine{{Pattern: "known ("}}
+	require.ErrorContains(t, v.Parse(context.Background(), []string{writeTestSuites(t, suite)}), `invalid quarantine pattern "known ("`)
+}
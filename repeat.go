@@ -0,0 +1,91 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// isRepeated returns true if the testcase has to be run several times, the setup and the teardown being run once
+func (v *Venom) isRepeated(ts *TestSuite, tc *TestCase) bool {
+	if tc == ts.Setup || tc == ts.Teardown {
+		return false
+	}
+	return v.Repeat > 1 || v.RepeatUntilFail
+}
+
+// runRepeatedTestCase runs the testcase v.Repeat times, stopping at its first failed attempt with RepeatUntilFail.
+// Without Repeat, the testcase is run until it fails, or until the run is cancelled.
+// The results of the steps are the ones of the last attempt.
+func (v *Venom) runRepeatedTestCase(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	tc.Repeat = &TestCaseRepeat{}
+	for n := 1; v.Repeat <= 0 || n <= v.Repeat; n++ {
+		if n > 1 {
+			if ctx.Err() != nil {
+				break
+			}
+			tc.TestStepResults = nil
+			tc.computedVerbose = nil
+			tc.Status = ""
+		}
+		if v.Verbose >= 1 {
+			v.Println(" \t  %s", Gray(fmt.Sprintf("[repeat] attempt %d", n)))
+		}
+
+		start := time.Now()
+		v.runTestCase(ctx, ts, tc)
+		tc.computeStatus()
+
+		attempt := TestCaseAttempt{
+			Number:   n,
+			Status:   tc.Status,
+			Start:    start,
+			Duration: time.Since(start).Seconds(),
+		}
+		for _, result := range tc.TestStepResults {
+			for _, failure := range result.Errors {
+				attempt.Errors = append(attempt.Errors, failure.Value)
+			}
+		}
+		tc.Repeat.Attempts = append(tc.Repeat.Attempts, attempt)
+
+		switch tc.Status {
+		case StatusPass:
+			tc.Repeat.Passed++
+		case StatusFail:
+			tc.Repeat.Failed++
+		}
+		// a skipped testcase is not repeated
+		if tc.Status == StatusSkip || (v.RepeatUntilFail && tc.Status == StatusFail) {
+			break
+		}
+	}
+
+	if total := tc.Repeat.Passed + tc.Repeat.Failed; total > 0 {
+		tc.Repeat.PassRate = float64(tc.Repeat.Passed) / float64(total)
+	}
+}
+
+// computeRepeatStatus flags the repeated testcase as flaky if it both passed and failed
+func (tc *TestCase) computeRepeatStatus() {
+	if tc.Repeat != nil && tc.Repeat.Passed > 0 && tc.Repeat.Failed > 0 {
+		tc.Status = StatusFlaky
+	}
+}
+
+// printRepeatAttempts prints the pass rate of the repeated testcase and its attempts.
+// Unless verbose is true, only the failed attempts are printed.
+func (v *Venom) printRepeatAttempts(tc *TestCase, indent string, verbose bool) {
+	if !verbose && tc.Repeat.Failed == 0 {
+		return
+	}
+	v.Println("%s\t\t%s", indent, Gray(fmt.Sprintf("[repeat] %d/%d passed (%.0f%%)", tc.Repeat.Passed, tc.Repeat.Passed+tc.Repeat.Failed, tc.Repeat.PassRate*100)))
+	for _, attempt := range tc.Repeat.Attempts {
+		if attempt.Status == StatusFail {
+			v.Println("%s\t\t%s", indent, Gray(fmt.Sprintf("[repeat] attempt %d (%.3fs): %s", attempt.Number, attempt.Duration, strings.Join(attempt.Errors, ", "))))
+		} else if verbose {
+			v.Println("%s\t\t%s", indent, Gray(fmt.Sprintf("[repeat] attempt %d (%.3fs): %s", attempt.Number, attempt.Duration, attempt.Status)))
+		}
+	}
+}
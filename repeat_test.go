@@ -0,0 +1,82 @@
+package venom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessRepeat(t *testing.T) {
+	suite := `name: suite
+setup:
+- type: counter
+testcases:
+- name: stable
+  steps:
+  - type: echo
+    value: foo
+- name: flaky
+  steps:
+  - type: counter
+    assertions:
+    - result.ok ShouldBeTrue
+`
+	tests := []struct {
+		name             string
+		repeat           int
+		untilFail        bool
+		wantStatus       Status
+		wantAttempts     int
+		wantPassRate     float64
+		wantStableRepeat int
+	}{
+		{name: "repeat", repeat: 4, wantStatus: StatusFlaky, wantAttempts: 4, wantPassRate: 0.5, wantStableRepeat: 4},
+		{name: "repeat until fail", repeat: 10, untilFail: true, wantStatus: StatusFlaky, wantAttempts: 2, wantPassRate: 0.5, wantStableRepeat: 10},
+		{name: "repeat once", repeat: 1, untilFail: true, wantStatus: StatusPass, wantAttempts: 1, wantPassRate: 1, wantStableRepeat: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the setup counts as the first call, so the attempts of the flaky testcase pass, fail, pass...
+			var calls int
+			counter := funcExecutor(func(ctx context.Context, step TestStep) (interface{}, error) {
+				calls++
+				return map[string]interface{}{"result": map[string]interface{}{"ok": calls%2 == 0}}, nil
+			})
+			v := newTestVenom(t, map[string]Executor{"echo": echoExecutor, "counter": counter})
+			v.Repeat = tt.repeat
+			v.RepeatUntilFail = tt.untilFail
+			runTestSuites(t, v, suite)
+
+			ts := v.Tests.TestSuites[0]
+			require.Equal(t, StatusPass, ts.Setup.Status)
+			require.Nil(t, ts.Setup.Repeat)
+
+			stable := ts.TestCases[0]
+			require.Equal(t, StatusPass, stable.Status)
+			require.Len(t, stable.Repeat.Attempts, tt.wantStableRepeat)
+			require.Equal(t, 1.0, stable.Repeat.PassRate)
+
+			flaky := ts.TestCases[1]
+			require.Equal(t, tt.wantStatus, flaky.Status)
+			require.Len(t, flaky.Repeat.Attempts, tt.wantAttempts)
+			require.Equal(t, tt.wantPassRate, flaky.Repeat.PassRate)
+			require.Equal(t, StatusPass, flaky.Repeat.Attempts[0].Status)
+			if tt.wantAttempts > 1 {
+				require.Equal(t, StatusFail, flaky.Repeat.Attempts[1].Status)
+				require.Len(t, flaky.Repeat.Attempts[1].Errors, 1)
+			}
+
+			if tt.wantStatus == StatusFlaky {
+				require.Equal(t, StatusFail, ts.Status)
+				require.Equal(t, 1, ts.NbTestcasesFlaky)
+				require.Equal(t, StatusFail, v.Tests.Status)
+
+				data, err := outputXMLFormat(v.Tests, 0)
+				require.NoError(t, err)
+				require.Contains(t, string(data), `<property name="pass_rate" value="0.50"></property>`)
+				require.Contains(t, string(data), `<error><![CDATA[attempt 2: Testcase "flaky"`)
+			}
+		})
+	}
+}
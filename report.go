@@ -0,0 +1,109 @@
+package venom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ReportFormats are the formats a report can be written in
+var ReportFormats = []string{"json", "tap", "xml", "yaml", "yml", "html"}
+
+// ReadReports reads the json reports written by OutputResult, the path being either a report,
+// or a directory containing test_results*.json reports
+func ReadReports(path string) ([]Tests, error) {
+	files := []string{path}
+	if fi, err := os.Stat(path); err != nil {
+		return nil, errors.Wrapf(err, "unable to read report %q", path)
+	} else if fi.IsDir() {
+		if files, err = filepath.Glob(filepath.Join(path, "test_results*.json")); err != nil {
+			return nil, errors.Wrapf(err, "unable to read report %q", path)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no json report found in %q", path)
+		}
+	}
+
+	reports := make([]Tests, 0, len(files))
+	for _, file := range files {
+		btes, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read report %q", file)
+		}
+		var tests Tests
+		if err := json.Unmarshal(btes, &tests); err != nil {
+			return nil, errors.Wrapf(err, "unable to read report %q", file)
+		}
+		reports = append(reports, tests)
+	}
+	return reports, nil
+}
+
+// MergeReports merges reports in a single one, and computes its counters and its status.
+// A testsuite found in several reports, identified by its file and its name, is kept once: the one which started last.
+func MergeReports(reports []Tests) *Tests {
+	merged := &Tests{TestSuites: []TestSuite{}}
+	index := map[string]int{}
+	for _, report := range reports {
+		if merged.Start.IsZero() || (!report.Start.IsZero() && report.Start.Before(merged.Start)) {
+			merged.Start = report.Start
+		}
+		if report.End.After(merged.End) {
+			merged.End = report.End
+		}
+		for _, ts := range report.TestSuites {
+			key := ts.Filepath + "/" + ts.Name
+			if i, ok := index[key]; ok {
+				if !ts.Start.Before(merged.TestSuites[i].Start) {
+					merged.TestSuites[i] = ts
+				}
+				continue
+			}
+			index[key] = len(merged.TestSuites)
+			merged.TestSuites = append(merged.TestSuites, ts)
+		}
+	}
+	merged.Duration = merged.End.Sub(merged.Start).Seconds()
+	merged.computeStatus()
+	return merged
+}
+
+// FormatReport formats the report in one of the ReportFormats
+func FormatReport(tests *Tests, format string, verbose int) ([]byte, error) {
+	var data []byte
+	var err error
+	switch format {
+	case "json":
+		data, err = json.MarshalIndent(tests, "", "  ")
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error: cannot format output json (%s)", err)
+		}
+	case "tap":
+		data, err = outputTapFormat(*tests)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error: cannot format output tap (%s)", err)
+		}
+	case "yml", "yaml":
+		data, err = yaml.Marshal(tests)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error: cannot format output yaml (%s)", err)
+		}
+	case "xml":
+		data, err = outputXMLFormat(*tests, verbose)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error: cannot format output xml (%s)", err)
+		}
+	case "html":
+		data, err = outputHTML(tests)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error: cannot format output html")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %q, must be one of %v", format, ReportFormats)
+	}
+	return data, nil
+}
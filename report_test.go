@@ -0,0 +1,70 @@
+package venom
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeReports(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	testSuite := func(name string, status Status, start time.Time) TestSuite {
+		return TestSuite{Name: name, Filepath: name + ".yml", Status: status, Start: start}
+	}
+	shard1 := Tests{
+		Start:      start,
+		End:        start.Add(time.Minute),
+		Shard:      &Shard{Index: 1, Total: 2},
+		TestSuites: []TestSuite{testSuite("a", StatusPass, start), testSuite("b", StatusFail, start)},
+	}
+	shard2 := Tests{
+		Start:      start.Add(10 * time.Second),
+		End:        start.Add(2 * time.Minute),
+		Shard:      &Shard{Index: 2, Total: 2},
+		TestSuites: []TestSuite{testSuite("c", StatusSkip, start)},
+	}
+	rerun := Tests{
+		Start:      start.Add(5 * time.Minute),
+		End:        start.Add(6 * time.Minute),
+		TestSuites: []TestSuite{testSuite("b", StatusPass, start.Add(5*time.Minute))},
+	}
+
+	dir := t.TempDir()
+	for name, report := range map[string]Tests{"test_results_1.json": shard1, "test_results_2.json": shard2, "rerun.json": rerun} {
+		btes, err := json.Marshal(report)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), btes, 0o644))
+	}
+	reports, err := ReadReports(dir)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	_, err = ReadReports(t.TempDir())
+	require.ErrorContains(t, err, "no json report found in")
+
+	merged := MergeReports(append(reports, rerun))
+	require.Len(t, merged.TestSuites, 3)
+	require.Equal(t, StatusPass, merged.Status)
+	require.Equal(t, 2, merged.NbTestsuitesPass)
+	require.Equal(t, 1, merged.NbTestsuitesSkip)
+	require.Equal(t, 0, merged.NbTestsuitesFail)
+	require.Equal(t, start, merged.Start)
+	require.Equal(t, 360.0, merged.Duration)
+	require.Nil(t, merged.Shard)
+
+	// the oldest result of a testsuite is ignored, whatever the order of the reports
+	merged = MergeReports([]Tests{rerun, shard1})
+	require.Equal(t, StatusPass, merged.Status)
+	require.Len(t, merged.TestSuites, 2)
+
+	for _, format := range ReportFormats {
+		data, err := FormatReport(merged, format, 0)
+		require.NoError(t, err, format)
+		require.NotEmpty(t, data, format)
+	}
+	_, err = FormatReport(merged, "csv", 0)
+	require.ErrorContains(t, err, `unsupported format "csv"`)
+}
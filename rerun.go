@@ -0,0 +1,143 @@
+package venom
+
+import "strings"
+
+// previousResults are the results of a previous run, read from the json reports written by OutputResult
+type previousResults struct {
+	source     string
+	testSuites []TestSuite
+}
+
+// readPreviousResults reads a json report, or all the json reports of a directory
+func readPreviousResults(path string) (*previousResults, error) {
+	reports, err := ReadReports(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// the testsuites are in both the reports of each testsuite and the aggregated reports
+	reports = []Tests{*MergeReports(reports)}
+	results := &previousResults{source: path}
+	for _, tests := range reports {
+		// the testcases of the report have all been evaluated, they are kept when merging the results
+		for i := range tests.TestSuites {
+			ts := &tests.TestSuites[i]
+			for j := range ts.TestCases {
+				ts.TestCases[j].IsEvaluated = true
+			}
+			for _, tc := range []*TestCase{ts.Setup, ts.Teardown} {
+				if tc != nil {
+					tc.IsEvaluated = true
+				}
+			}
+		}
+		results.testSuites = append(results.testSuites, tests.TestSuites...)
+	}
+	return results, nil
+}
+
+// testSuite returns the previous result of a testsuite, found by its path, its filename or its name
+func (r *previousResults) testSuite(ts TestSuite) *TestSuite {
+	for _, match := range []func(previous TestSuite) bool{
+		func(previous TestSuite) bool { return previous.Filepath == ts.Filepath && previous.Name == ts.Name },
+		func(previous TestSuite) bool { return previous.Filename == ts.Filename && previous.Name == ts.Name },
+		func(previous TestSuite) bool { return previous.Name == ts.Name },
+	} {
+		for i := range r.testSuites {
+			if match(r.testSuites[i]) {
+				return &r.testSuites[i]
+			}
+		}
+	}
+	return nil
+}
+
+// addVars adds the variables of the previous run of the testsuite read from filePath, if they are not already defined
+func (r *previousResults) addVars(filePath string, vars H) {
+	for i := range r.testSuites {
+		previous := r.testSuites[i]
+		if previous.Filepath != filePath {
+			continue
+		}
+		for k, value := range previous.Vars {
+			if _, ok := vars[k]; !ok && !strings.HasPrefix(k, "venom.") {
+				vars.Add(k, value)
+			}
+		}
+		return
+	}
+}
+
+// failedTestCases returns the testcases of the testsuite to rerun: the testcases which failed in the previous run,
+// or which were not run because of a failed setup, and the testcases they depend on
+func (r *previousResults) failedTestCases(ts *TestSuite) map[int]struct{} {
+	rerun := map[int]struct{}{}
+	previous := r.testSuite(*ts)
+	if previous == nil {
+		return rerun
+	}
+
+	failed := map[string]struct{}{}
+	setupFailed := previous.Setup != nil && previous.Setup.Status == StatusFail
+	for _, tc := range previous.TestCases {
+		if tc.Status == StatusFail || tc.Status == StatusFlaky || (setupFailed && tc.Status != StatusPass) {
+			failed[tc.Name] = struct{}{}
+		}
+	}
+
+	for i, tc := range ts.TestCases {
+		if _, ok := failed[tc.Name]; ok {
+			ts.selectWithDependencies(rerun, i)
+		}
+	}
+	return rerun
+}
+
+// mergeRerunResults merges the results of the testcases rerun into the previous results.
+// The testcases which failed in the previous run and pass now are marked as passed on rerun.
+func (r *previousResults) mergeRerunResults(tests *Tests) {
+	merged := make([]TestSuite, len(r.testSuites))
+	copy(merged, r.testSuites)
+	for _, ts := range tests.TestSuites {
+		var previous *TestSuite
+		for i := range merged {
+			if merged[i].Name == ts.Name && merged[i].Filename == ts.Filename {
+				previous = &merged[i]
+				break
+			}
+		}
+		if previous == nil {
+			merged = append(merged, ts)
+			continue
+		}
+
+		for _, tc := range ts.TestCases {
+			if !tc.rerun {
+				continue
+			}
+			tc.PassedOnRerun = tc.Status == StatusPass
+			replaced := false
+			for i := range previous.TestCases {
+				if previous.TestCases[i].Name == tc.Name {
+					tc.PassedOnRerun = tc.PassedOnRerun && previous.TestCases[i].Status != StatusPass
+					previous.TestCases[i] = tc
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				previous.TestCases = append(previous.TestCases, tc)
+			}
+		}
+		if ts.Setup != nil && len(ts.Setup.Skipped) == 0 {
+			previous.Setup = ts.Setup
+		}
+		if ts.Teardown != nil && len(ts.Teardown.Skipped) == 0 {
+			previous.Teardown = ts.Teardown
+		}
+		previous.computeStatus()
+	}
+
+	tests.TestSuites = merged
+	tests.computeStatus()
+}
@@ -0,0 +1,84 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessRerunFailed(t *testing.T) {
+	dir := writeTestSuites(t, `name: suite
+testcases:
+- name: create account
+  steps:
+  - type: echo
+    value: 42
+    vars:
+      id:
+        from: result.value
+- name: ok
+  steps:
+  - type: echo
+    value: foo
+- name: flaky
+  depends_on: [create account]
+  steps:
+  - type: echo
+    value: "{{.env}}-{{.create-account.id}}-{{.attempt}}"
+    assertions:
+    - result.value ShouldEqual staging-42-2
+`, `name: passing suite
+testcases:
+- name: ok
+  steps:
+  - type: echo
+    value: foo
+`)
+
+	// the first run fails, and writes its json reports
+	reportDir := t.TempDir()
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.AddVariables(map[string]interface{}{"env": "staging", "attempt": 1})
+	v.OutputFormat = "json"
+	v.OutputDir = reportDir
+	require.NoError(t, v.Parse(context.Background(), []string{dir}))
+	require.NoError(t, v.Process(context.Background(), []string{dir}))
+	require.NoError(t, v.OutputResult())
+	require.Equal(t, StatusFail, v.Tests.Status)
+
+	for _, merge := range []bool{false, true} {
+		t.Run(fmt.Sprintf("merge %t", merge), func(t *testing.T) {
+			// env is not given anymore, its value is read from the previous results
+			v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+			v.AddVariables(map[string]interface{}{"attempt": 2})
+			v.RerunFailed = reportDir
+			v.RerunMerge = merge
+			require.NoError(t, v.Parse(context.Background(), []string{dir}))
+			require.NoError(t, v.Process(context.Background(), []string{dir}))
+
+			require.Equal(t, StatusPass, v.Tests.Status)
+			ts := testSuiteByName(t, v, "suite")
+			require.Equal(t, StatusPass, ts.TestCases[0].Status)
+			require.False(t, ts.TestCases[0].PassedOnRerun)
+			require.Equal(t, StatusPass, ts.TestCases[2].Status)
+			require.Equal(t, merge, ts.TestCases[2].PassedOnRerun)
+			passing := testSuiteByName(t, v, "passing suite")
+			if merge {
+				require.Equal(t, StatusPass, ts.TestCases[1].Status)
+				require.Equal(t, 3, ts.NbTestcasesPass)
+				require.Equal(t, StatusPass, passing.Status)
+				require.Equal(t, 2, v.Tests.NbTestsuitesPass)
+			} else {
+				require.Equal(t, StatusSkip, ts.TestCases[1].Status)
+				require.Equal(t, "===== suite/ok did not fail in "+reportDir+" =====", ts.TestCases[1].Skipped[0].Value)
+				require.Equal(t, StatusSkip, passing.Status)
+			}
+		})
+	}
+
+	v = newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	v.RerunFailed = t.TempDir()
+	require.ErrorContains(t, v.Parse(context.Background(), []string{dir}), "no json report found in")
+}
@@ -0,0 +1,357 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ovh/venom/assertions"
+)
+
+// SchemaVersion is the JSON Schema draft of the schema written by Schema
+const SchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// schema is a JSON Schema, or a part of it
+type schema map[string]interface{}
+
+var (
+	durationType    = reflect.TypeOf(Duration(0))
+	timeDuration    = reflect.TypeOf(time.Duration(0))
+	timeType        = reflect.TypeOf(time.Time{})
+	assertionType   = reflect.TypeOf((*Assertion)(nil)).Elem()
+	rawMessageType  = reflect.TypeOf(json.RawMessage{})
+	includesType    = reflect.TypeOf(Includes{})
+	interfaceType   = reflect.TypeOf((*interface{})(nil)).Elem()
+	assignmentsType = reflect.TypeOf(map[string]Assignment{})
+)
+
+func ref(name string) schema {
+	return schema{"$ref": "#/definitions/" + name}
+}
+
+// Schema returns the JSON Schema of the testsuites, for the editors to validate and complete them.
+// The steps are checked according to their type: the attributes of the builtin executors come from their fields,
+// and the ones of the user executors of the lib directories from their input.
+func (v *Venom) Schema(ctx context.Context) (map[string]interface{}, error) {
+	definitions := schema{
+		"template": schema{
+			"description": "a template expression, interpolated before running the step",
+			"type":        "string",
+			"pattern":     `\{\{.*\}\}`,
+		},
+		"duration": schema{
+			"description": "a number of seconds, or a duration such as 500ms or 1m30s",
+			"type":        []string{"number", "string"},
+		},
+		"assertion":  assertionSchema(),
+		"assertions": schema{"type": "array", "items": ref("assertion")},
+		"include": schema{
+			"anyOf": []interface{}{
+				schema{"type": "string"},
+				schema{
+					"type": "object",
+					"properties": schema{
+						"file":  schema{"type": "string"},
+						"input": schema{"type": "object"},
+					},
+					"required":             []string{"file"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"includes": schema{
+			"anyOf": []interface{}{ref("include"), schema{"type": "array", "items": ref("include")}},
+		},
+		"assignment": schema{
+			"type": "object",
+			"properties": schema{
+				"from":    schema{"type": "string", "description": "the result to assign, such as result.systemout"},
+				"regex":   schema{"type": "string", "description": "the value assigned is the first group of the regex matching the result"},
+				"default": schema{"description": "the value assigned if the result is not found"},
+			},
+			"additionalProperties": false,
+		},
+	}
+
+	testSuite := structSchema(reflect.TypeOf(TestSuiteInput{}), []string{"json"}, map[reflect.Type]bool{})
+	setProperty(testSuite, "setup", schema{"type": "array", "items": ref("step")})
+	setProperty(testSuite, "teardown", schema{"type": "array", "items": ref("step")})
+	setProperty(testSuite, "testcases", schema{"type": "array", "items": ref("testcase")})
+
+	testCase := structSchema(reflect.TypeOf(TestCaseInput{}), []string{"json"}, map[reflect.Type]bool{})
+	setProperty(testCase, "steps", schema{"type": "array", "items": ref("step")})
+	setProperty(testCase, "finally", schema{"type": "array", "items": ref("step")})
+	setProperty(testCase, "skip", ref("assertions"))
+	definitions["testcase"] = testCase
+
+	executors, err := v.executorSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(executors))
+	for name := range executors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// the steps are a union discriminated by their type, exec being the type of the steps with a script and no type
+	union := []interface{}{
+		schema{
+			"if":   schema{"required": []string{"include"}},
+			"then": includeStepSchema(),
+		},
+	}
+	for _, name := range names {
+		definitions["executor."+name] = executors[name]
+		union = append(union, schema{
+			"if": schema{
+				"properties": schema{"type": schema{"const": name}},
+				"required":   []string{"type"},
+			},
+			"then": ref("executor." + name),
+		})
+	}
+	if _, ok := executors["exec"]; ok {
+		union = append(union, schema{
+			"if":   schema{"not": schema{"anyOf": []interface{}{schema{"required": []string{"type"}}, schema{"required": []string{"include"}}}}},
+			"then": ref("executor.exec"),
+		})
+	}
+	definitions["step"] = schema{
+		"type": "object",
+		"properties": schema{
+			"type": schema{
+				"description": "the executor of the step",
+				"anyOf":       []interface{}{schema{"enum": names}, schema{"type": "string"}},
+			},
+		},
+		"allOf": union,
+	}
+
+	testSuite["$schema"] = SchemaVersion
+	testSuite["title"] = "venom testsuite"
+	testSuite["definitions"] = definitions
+	return testSuite, nil
+}
+
+// executorSchemas returns the schemas of the steps of the builtin executors and of the user executors
+func (v *Venom) executorSchemas(ctx context.Context) (map[string]schema, error) {
+	executors := map[string]schema{}
+	for name, e := range v.executorsBuiltin {
+		s := schema{"type": "object", "properties": schema{}}
+		t := reflect.TypeOf(e)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		// the attributes of the executors which are not structs are unknown
+		if t != nil && t.Kind() == reflect.Struct {
+			s = structSchema(t, []string{"mapstructure", "yaml", "json"}, map[reflect.Type]bool{})
+		}
+		executors[name] = stepSchema(name, s)
+	}
+
+	vars, err := DumpStringPreserveCase(v.variables)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range v.getUserExecutorFilesPath(ctx, vars) {
+		ux, err := readUserExecutor(f)
+		if err != nil {
+			return nil, err
+		}
+		var inputs struct {
+			Input map[string]interface{} `yaml:"input"`
+		}
+		// the default values of the inputs can be template expressions
+		if err := yaml.Unmarshal(quoteTemplateExpressions(ux.RawInputs), &inputs); err != nil {
+			Warn(ctx, "unable to read the input of the user executor %q: %v", ux.Executor, err)
+		}
+		properties := schema{}
+		for k, value := range inputs.Input {
+			p := schema{"description": "input of the user executor " + ux.Executor}
+			switch value.(type) {
+			case nil, map[string]interface{}:
+			default:
+				p["default"] = value
+			}
+			properties[k] = p
+		}
+		executors[ux.Executor] = stepSchema(ux.Executor, schema{"type": "object", "properties": properties, "additionalProperties": false})
+	}
+	return executors, nil
+}
+
+// stepSchema adds the attributes handled by venom for all the steps to the schema of the attributes of an executor
+func stepSchema(name string, s schema) schema {
+	properties := s["properties"].(schema)
+	properties["type"] = schema{"const": name}
+	properties["name"] = schema{"type": "string"}
+	properties["info"] = schema{"anyOf": []interface{}{schema{"type": "string"}, schema{"type": "array", "items": schema{"type": "string"}}}}
+	properties["assertions"] = ref("assertions")
+	properties["vars"] = schema{"type": "object", "additionalProperties": ref("assignment")}
+	properties["extracts"] = schema{"type": "object", "additionalProperties": schema{"type": "string"}}
+	properties["range"] = schema{"description": "a list, a map, a number of iterations or a template expression"}
+	properties["skip"] = ref("assertions")
+	properties["retry"] = scalarSchema("integer")
+	properties["retry_if"] = ref("assertions")
+	properties["retry_on"] = schema{"type": "array", "items": schema{"enum": []string{retryOnError, retryOnAssertion}}}
+	properties["retry_backoff"] = schema{"enum": []string{"constant", "exponential"}}
+	properties["retry_jitter"] = ref("duration")
+	properties["delay"] = ref("duration")
+	properties["timeout"] = ref("duration")
+	properties["until"] = structSchema(reflect.TypeOf(StepUntil{}), []string{"json"}, map[reflect.Type]bool{})
+	return s
+}
+
+func includeStepSchema() schema {
+	return schema{
+		"type": "object",
+		"properties": schema{
+			"include": schema{"type": "string", "description": "the file of the steps to include"},
+			"input":   schema{"type": "object", "description": "the variables of the included file to override"},
+		},
+		"additionalProperties": false,
+	}
+}
+
+// assertionSchema matches the assertions written as strings, and the logical operators on lists of assertions
+func assertionSchema() schema {
+	var operators []string
+	for _, name := range assertions.Names() {
+		operators = append(operators, strings.TrimPrefix(name, "Should"))
+	}
+	return schema{
+		"anyOf": []interface{}{
+			schema{
+				"type":    "string",
+				"pattern": `^\s*\S+\s+(?:Should|Must)(?:` + strings.Join(operators, "|") + `)(?:\s.*)?$|\{\{`,
+			},
+			schema{
+				"type": "object",
+				"properties": schema{
+					"and": ref("assertions"),
+					"or":  ref("assertions"),
+					"xor": ref("assertions"),
+					"not": ref("assertions"),
+				},
+				"minProperties":        1,
+				"maxProperties":        1,
+				"additionalProperties": false,
+			},
+		},
+	}
+}
+
+// setProperty replaces the schema of a property of an object
+func setProperty(s schema, name string, p schema) {
+	s["properties"].(schema)[name] = p
+}
+
+// scalarSchema returns the schema of a value of a JSON type, which can also be a template expression
+func scalarSchema(jsonType string) schema {
+	return schema{"anyOf": []interface{}{schema{"type": jsonType}, ref("template")}}
+}
+
+// structSchema returns the schema of the objects decoded into the struct t.
+// The names of the attributes are read from the first of the tags which is set, or are the lowercased names of the fields.
+func structSchema(t reflect.Type, tags []string, visited map[reflect.Type]bool) schema {
+	properties := schema{}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !visited[ft] {
+				for k, p := range structSchema(ft, tags, visited)["properties"].(schema) {
+					properties[k] = p
+				}
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.ToLower(f.Name)
+		for _, tag := range tags {
+			if value, ok := f.Tag.Lookup(tag); ok {
+				if value = strings.Split(value, ",")[0]; value != "" {
+					name = value
+					break
+				}
+			}
+		}
+		if name == "-" {
+			continue
+		}
+		if p := typeSchema(f.Type, tags, visited); p != nil {
+			properties[name] = p
+		}
+	}
+	return schema{"type": "object", "properties": properties, "additionalProperties": false}
+}
+
+// typeSchema returns the schema of the values decoded into the type t, or nil if t cannot be decoded
+func typeSchema(t reflect.Type, tags []string, visited map[reflect.Type]bool) schema {
+	switch t {
+	case durationType, timeDuration:
+		return ref("duration")
+	case timeType:
+		return schema{"type": "string"}
+	case assertionType:
+		return ref("assertion")
+	case includesType:
+		return ref("includes")
+	case rawMessageType, interfaceType:
+		return schema{}
+	case assignmentsType:
+		return schema{"type": "object", "additionalProperties": ref("assignment")}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem(), tags, visited)
+	case reflect.String:
+		return schema{"type": "string"}
+	case reflect.Bool:
+		return scalarSchema("boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return scalarSchema("integer")
+	case reflect.Float32, reflect.Float64:
+		return scalarSchema("number")
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return schema{"type": "string"}
+		}
+		items := typeSchema(t.Elem(), tags, visited)
+		if items == nil {
+			return nil
+		}
+		return schema{"type": "array", "items": items}
+	case reflect.Map:
+		values := typeSchema(t.Elem(), tags, visited)
+		if values == nil {
+			return nil
+		}
+		return schema{"type": "object", "additionalProperties": values}
+	case reflect.Struct:
+		// the recursive types are not described further
+		if visited[t] {
+			return schema{"type": "object"}
+		}
+		return structSchema(t, tags, visited)
+	case reflect.Interface:
+		return schema{}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"lib/user.yml": `executor: user
+input:
+  url: http://localhost
+  id: {}
+steps:
+- type: value
+  value: '{{.input.url}}/{{.input.id}}'
+`,
+	})
+	v := newTestVenom(t, map[string]Executor{"value": valueExecutor{}, "echo": echoExecutor})
+	v.LibDir = filepath.Join(dir, "lib")
+
+	s, err := v.Schema(context.Background())
+	require.NoError(t, err)
+	btes, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	var got struct {
+		Properties  map[string]interface{} `json:"properties"`
+		Definitions struct {
+			Step struct {
+				AllOf []struct {
+					If   map[string]interface{} `json:"if"`
+					Then map[string]interface{} `json:"then"`
+				} `json:"allOf"`
+			} `json:"step"`
+			Assertion struct {
+				AnyOf []map[string]interface{} `json:"anyOf"`
+			} `json:"assertion"`
+			Value struct {
+				Properties           map[string]interface{} `json:"properties"`
+				AdditionalProperties bool                   `json:"additionalProperties"`
+			} `json:"executor.value"`
+			Echo struct {
+				Properties           map[string]interface{} `json:"properties"`
+				AdditionalProperties *bool                  `json:"additionalProperties"`
+			} `json:"executor.echo"`
+			User struct {
+				Properties map[string]map[string]interface{} `json:"properties"`
+			} `json:"executor.user"`
+			TestCase struct {
+				Properties map[string]map[string]interface{} `json:"properties"`
+			} `json:"testcase"`
+		} `json:"definitions"`
+	}
+	require.NoError(t, json.Unmarshal(btes, &got))
+
+	require.Contains(t, got.Properties, "testcases")
+	require.Contains(t, got.Properties, "setup")
+	require.Equal(t, map[string]interface{}{"$ref": "#/definitions/step"}, got.Definitions.TestCase.Properties["steps"]["items"])
+
+	// the steps are checked according to their type
+	var thens []interface{}
+	for _, c := range got.Definitions.Step.AllOf {
+		thens = append(thens, c.Then["$ref"])
+	}
+	require.Contains(t, thens, "#/definitions/executor.value")
+	require.Contains(t, thens, "#/definitions/executor.user")
+
+	require.Contains(t, got.Definitions.Value.Properties, "value")
+	require.Contains(t, got.Definitions.Value.Properties, "assertions")
+	require.Equal(t, map[string]interface{}{"const": "value"}, got.Definitions.Value.Properties["type"])
+	require.False(t, got.Definitions.Value.AdditionalProperties)
+	// the attributes of the executors which are not structs are not known
+	require.Nil(t, got.Definitions.Echo.AdditionalProperties)
+
+	require.Contains(t, got.Definitions.User.Properties, "id")
+	require.Equal(t, "http://localhost", got.Definitions.User.Properties["url"]["default"])
+
+	pattern := regexp.MustCompile(got.Definitions.Assertion.AnyOf[0]["pattern"].(string))
+	require.True(t, pattern.MatchString("result.code ShouldEqual 0"))
+	require.True(t, pattern.MatchString("result.body MustContainSubstring foo"))
+	require.True(t, pattern.MatchString("result.body ShouldBeEmpty"))
+	require.True(t, pattern.MatchString("result.code {{.op}} 0"))
+	require.False(t, pattern.MatchString("result.code ShouldBeFoo 0"))
+	require.False(t, pattern.MatchString("result.code"))
+}
@@ -0,0 +1,155 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Shard is the part of the testsuites run by a job, when they are split across several jobs
+type Shard struct {
+	// Index of the shard, from 1 to Total
+	Index int `json:"index" yaml:"index"`
+	Total int `json:"total" yaml:"total"`
+}
+
+func (s Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Total)
+}
+
+// ParseShard parses a shard such as "3/8", the third shard of eight
+func ParseShard(s string) (*Shard, error) {
+	index, total, ok := strings.Cut(s, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid shard %q, must be i/n such as 3/8", s)
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(index))
+	if err != nil {
+		return nil, fmt.Errorf("invalid shard %q, must be i/n such as 3/8", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(total))
+	if err != nil {
+		return nil, fmt.Errorf("invalid shard %q, must be i/n such as 3/8", s)
+	}
+	if n < 1 || i < 1 || i > n {
+		return nil, fmt.Errorf("invalid shard %q, the index must be between 1 and %d", s, n)
+	}
+	return &Shard{Index: i, Total: n}, nil
+}
+
+// shardFiles returns the files of the shard of v.
+// The files are split by count, or balanced by the durations of their testsuites read from ShardTimings.
+func (v *Venom) shardFiles(ctx context.Context, filesPath []string) ([]string, error) {
+	files := make([]string, len(filesPath))
+	copy(files, filesPath)
+	sort.Strings(files)
+
+	var durations map[string]float64
+	if v.ShardTimings != "" {
+		previous, err := readPreviousResults(v.ShardTimings)
+		if err != nil {
+			return nil, err
+		}
+		durations = previous.fileDurations(files)
+	}
+
+	shards := splitFiles(files, v.Shard.Total, durations)
+	Info(ctx, "Shard %s: %d of %d files", v.Shard, len(shards[v.Shard.Index-1]), len(files))
+	return shards[v.Shard.Index-1], nil
+}
+
+// splitFiles splits the sorted files in n shards. Without durations, the files are dealt in turn to each shard.
+// With durations, the longest files are assigned first to the shard with the lowest total duration.
+func splitFiles(files []string, n int, durations map[string]float64) [][]string {
+	shards := make([][]string, n)
+	if durations == nil {
+		for i, f := range files {
+			shards[i%n] = append(shards[i%n], f)
+		}
+		return shards
+	}
+
+	byDuration := make([]string, len(files))
+	copy(byDuration, files)
+	sort.SliceStable(byDuration, func(i, j int) bool {
+		return durations[byDuration[i]] > durations[byDuration[j]]
+	})
+	totals := make([]float64, n)
+	for _, f := range byDuration {
+		lowest := 0
+		for i := range totals {
+			if totals[i] < totals[lowest] {
+				lowest = i
+			}
+		}
+		shards[lowest] = append(shards[lowest], f)
+		totals[lowest] += durations[f]
+	}
+	for i := range shards {
+		sort.Strings(shards[i])
+	}
+	return shards
+}
+
+// fileDurations returns the duration of the testsuites of each file in the previous results.
+// The files not found in the previous results, new ones for instance, get the average duration.
+func (r *previousResults) fileDurations(files []string) map[string]float64 {
+	durations := make(map[string]float64, len(files))
+	// counted are the previous testsuites whose duration is already assigned to a file
+	counted := make(map[int]bool, len(r.testSuites))
+	found := make(map[string]bool, len(files))
+	for _, f := range files {
+		for i, ts := range r.testSuites {
+			if ts.Filepath == f {
+				durations[f] += ts.Duration
+				counted[i] = true
+				found[f] = true
+			}
+		}
+	}
+
+	// the files may have been given with another path in the previous run,
+	// they are found by their name if only one previous testsuite has this name
+	for _, f := range files {
+		if found[f] {
+			continue
+		}
+		match := -1
+		for i, ts := range r.testSuites {
+			if ts.Filename != filepath.Base(f) {
+				continue
+			}
+			if match >= 0 {
+				match = -1
+				break
+			}
+			match = i
+		}
+		if match >= 0 && !counted[match] {
+			durations[f] = r.testSuites[match].Duration
+			counted[match] = true
+			found[f] = true
+		}
+	}
+
+	var unknown []string
+	var sum float64
+	for _, f := range files {
+		if !found[f] {
+			unknown = append(unknown, f)
+			continue
+		}
+		sum += durations[f]
+	}
+	average := 1.0
+	if known := len(files) - len(unknown); known > 0 && sum > 0 {
+		average = sum / float64(known)
+	}
+	for _, f := range unknown {
+		durations[f] = average
+	}
+	return durations
+}
@@ -0,0 +1,79 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShard(t *testing.T) {
+	shard, err := ParseShard("3/8")
+	require.NoError(t, err)
+	require.Equal(t, &Shard{Index: 3, Total: 8}, shard)
+	require.Equal(t, "3/8", shard.String())
+
+	for _, s := range []string{"3", "a/8", "3/b", "0/8", "9/8", "1/0"} {
+		_, err := ParseShard(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestSplitFiles(t *testing.T) {
+	files := []string{"a.yml", "b.yml", "c.yml", "d.yml", "e.yml"}
+	require.Equal(t, [][]string{{"a.yml", "c.yml", "e.yml"}, {"b.yml", "d.yml"}}, splitFiles(files, 2, nil))
+	require.Equal(t, [][]string{{"a.yml"}, {"b.yml"}, {"c.yml"}, {"d.yml"}, {"e.yml"}, nil}, splitFiles(files, 6, nil))
+
+	durations := map[string]float64{"a.yml": 60, "b.yml": 10, "c.yml": 20, "d.yml": 30, "e.yml": 5}
+	require.Equal(t, [][]string{{"a.yml", "e.yml"}, {"b.yml", "c.yml", "d.yml"}}, splitFiles(files, 2, durations))
+	require.Equal(t, [][]string{{"a.yml"}, {"d.yml", "e.yml"}, {"b.yml", "c.yml"}}, splitFiles(files, 3, durations))
+}
+
+func TestFileDurations(t *testing.T) {
+	previous := &previousResults{testSuites: []TestSuite{
+		{Filename: "smoke.yml", Filepath: "a/smoke.yml", Duration: 10},
+		{Filename: "smoke.yml", Filepath: "b/smoke.yml", Duration: 20},
+		{Filename: "api.yml", Filepath: "other/api.yml", Duration: 30},
+		{Filename: "login.yml", Filepath: "x/login.yml", Duration: 40},
+	}}
+	// c/smoke.yml matches two previous testsuites by its name, and y/login.yml one already counted for x/login.yml:
+	// they get the average duration of the other ones
+	require.Equal(t, map[string]float64{
+		"c/smoke.yml": 35,
+		"new/api.yml": 30,
+		"x/login.yml": 40,
+		"y/login.yml": 35,
+	}, previous.fileDurations([]string{"c/smoke.yml", "new/api.yml", "x/login.yml", "y/login.yml"}))
+}
+
+func TestParseShardTimings(t *testing.T) {
+	dir := writeTestSuites(t, "name: suite 0", "name: suite 1", "name: suite 2", "name: suite 3")
+	report := Tests{TestSuites: []TestSuite{
+		{Name: "suite 0", Filepath: filepath.Join(dir, "testsuite_0.yml"), Duration: 100},
+		{Name: "suite 1", Filename: "testsuite_1.yml", Filepath: "other/testsuite_1.yml", Duration: 30},
+		{Name: "suite 2", Filepath: filepath.Join(dir, "testsuite_2.yml"), Duration: 40},
+	}}
+	btes, err := json.Marshal(report)
+	require.NoError(t, err)
+	timings := filepath.Join(t.TempDir(), "test_results.json")
+	require.NoError(t, os.WriteFile(timings, btes, 0o644))
+
+	// suite 3 is new, and gets the average duration of the other ones
+	for shard, want := range map[string][]string{"1/2": {"suite 0"}, "2/2": {"suite 1", "suite 2", "suite 3"}} {
+		v := newTestVenom(t, nil)
+		v.Shard, err = ParseShard(shard)
+		require.NoError(t, err)
+		v.ShardTimings = timings
+		require.NoError(t, v.Parse(context.Background(), []string{dir}))
+
+		var names []string
+		for _, ts := range v.Tests.TestSuites {
+			names = append(names, ts.Name)
+		}
+		require.ElementsMatch(t, want, names, shard)
+		require.Equal(t, v.Shard, v.Tests.Shard)
+	}
+}
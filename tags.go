@@ -0,0 +1,169 @@
+package venom
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tagExpression is a boolean expression over the tags of a testcase,
+// e.g. "smoke,critical" or "(payments || orders) && !slow".
+type tagExpression func(tags map[string]struct{}) bool
+
+// parseTagExpression compiles a tag expression. The operators are, by increasing precedence:
+//   - "," "|" "||" "or": at least one of the operands matches
+//   - "&" "&&" "and": all the operands match
+//   - "!" "not": the operand does not match
+//
+// Parentheses can be used to group expressions. An empty expression returns nil.
+func parseTagExpression(s string) (tagExpression, error) {
+	tokens, err := tokenizeTagExpression(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &tagExpressionParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tags expression %q: %v", s, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid tags expression %q: unexpected %q", s, p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+// match returns true if the expression matches the given tags
+func (e tagExpression) match(tags []string) bool {
+	set := make(map[string]struct{}, len(tags))
+	for _, t := range tags {
+		set[t] = struct{}{}
+	}
+	return e(set)
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.:/@", r)
+}
+
+func tokenizeTagExpression(s string) ([]string, error) {
+	var tokens []string
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == '!' || r == ',':
+			tokens = append(tokens, string(r))
+			i++
+		case r == '&' || r == '|':
+			// "&&" and "||" are aliases of "&" and "|"
+			tokens = append(tokens, string(r))
+			i++
+			if i < len(runes) && runes[i] == r {
+				i++
+			}
+		case isTagRune(r):
+			start := i
+			for i < len(runes) && isTagRune(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			switch strings.ToLower(word) {
+			case "and":
+				word = "&"
+			case "or":
+				word = "|"
+			case "not":
+				word = "!"
+			}
+			tokens = append(tokens, word)
+		default:
+			return nil, fmt.Errorf("invalid tags expression %q: unexpected character %q", s, r)
+		}
+	}
+	return tokens, nil
+}
+
+type tagExpressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *tagExpressionParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *tagExpressionParser) parseOr() (tagExpression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.next() == "," || p.next() == "|" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(tags map[string]struct{}) bool { return l(tags) || right(tags) }
+	}
+	return left, nil
+}
+
+func (p *tagExpressionParser) parseAnd() (tagExpression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.next() == "&" {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(tags map[string]struct{}) bool { return l(tags) && right(tags) }
+	}
+	return left, nil
+}
+
+func (p *tagExpressionParser) parseNot() (tagExpression, error) {
+	switch tok := p.next(); tok {
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case "!":
+		p.pos++
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(tags map[string]struct{}) bool { return !expr(tags) }, nil
+	case "(":
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return expr, nil
+	case ")", ",", "|", "&":
+		return nil, fmt.Errorf("unexpected %q", tok)
+	default:
+		p.pos++
+		return func(tags map[string]struct{}) bool {
+			_, ok := tags[tok]
+			return ok
+		}, nil
+	}
+}
@@ -0,0 +1,47 @@
+package venom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTagExpression(t *testing.T) {
+	tests := []struct {
+		expr    string
+		tags    []string
+		want    bool
+		wantErr bool
+	}{
+		{expr: "smoke", tags: []string{"smoke"}, want: true},
+		{expr: "smoke", tags: []string{"slow"}, want: false},
+		{expr: "smoke,critical", tags: []string{"critical"}, want: true},
+		{expr: "smoke || critical", tags: []string{"slow"}, want: false},
+		{expr: "smoke && !slow", tags: []string{"smoke"}, want: true},
+		{expr: "smoke && !slow", tags: []string{"smoke", "slow"}, want: false},
+		{expr: "smoke and not slow", tags: []string{"smoke", "slow"}, want: false},
+		{expr: "(payments | orders) & smoke", tags: []string{"orders", "smoke"}, want: true},
+		{expr: "payments | orders & smoke", tags: []string{"payments"}, want: true},
+		{expr: "!(payments, orders)", tags: []string{"orders"}, want: false},
+		{expr: "team:billing", tags: []string{"team:billing"}, want: true},
+		{expr: "smoke &&", wantErr: true},
+		{expr: "(smoke", wantErr: true},
+		{expr: "smoke slow", wantErr: true},
+		{expr: "smoke$", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := parseTagExpression(tt.expr)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, expr.match(tt.tags))
+		})
+	}
+
+	expr, err := parseTagExpression(" ")
+	require.NoError(t, err)
+	require.Nil(t, expr)
+}
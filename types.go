@@ -6,11 +6,13 @@ import (
 	"encoding/xml"
 	"fmt"
 	"maps"
+	"slices"
 	"strings"
 	"time"
 	"unicode"
 
 	"github.com/fatih/color"
+	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 )
 
@@ -21,6 +23,10 @@ const (
 	StatusFail Status = "FAIL"
 	StatusSkip Status = "SKIP"
 	StatusPass Status = "PASS"
+	// StatusFlaky is the status of a repeated testcase which both passed and failed
+	StatusFlaky Status = "FLAKY"
+	// StatusQuarantined is the status of a testcase in quarantine which has run, whatever its result
+	StatusQuarantined Status = "QUARANTINED"
 )
 
 type H map[string]interface{}
@@ -67,6 +73,42 @@ type StepAssertions struct {
 	Assertions []Assertion `json:"assertions,omitempty" yaml:"assertions,omitempty"`
 }
 
+// StepUntil is the condition polled by a step: the step is run until the assertions of the condition are met,
+// then the assertions of the step are applied
+type StepUntil struct {
+	Assertions  []Assertion `json:"assertions,omitempty" yaml:"assertions,omitempty"`
+	Interval    Duration    `json:"interval,omitempty" yaml:"interval,omitempty"`
+	MaxDuration Duration    `json:"max_duration,omitempty" yaml:"max_duration,omitempty"`
+	// Backoff is "constant" (default) or "exponential" to double the interval after each attempt
+	Backoff string `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+}
+
+// UntilAttempt is an attempt of a step polling its until condition
+type UntilAttempt struct {
+	Number   int       `json:"number" yaml:"number"`
+	Start    time.Time `json:"start" yaml:"start"`
+	Duration float64   `json:"duration" yaml:"duration"`
+	OK       bool      `json:"ok" yaml:"ok"`
+	Errors   []string  `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// TestCaseRepeat is the result of a testcase run several times with --repeat
+type TestCaseRepeat struct {
+	Attempts []TestCaseAttempt `json:"attempts" yaml:"attempts"`
+	Passed   int               `json:"passed" yaml:"passed"`
+	Failed   int               `json:"failed" yaml:"failed"`
+	PassRate float64           `json:"passRate" yaml:"passRate"`
+}
+
+// TestCaseAttempt is a run of a repeated testcase
+type TestCaseAttempt struct {
+	Number   int       `json:"number" yaml:"number"`
+	Status   Status    `json:"status" yaml:"status"`
+	Start    time.Time `json:"start" yaml:"start"`
+	Duration float64   `json:"duration" yaml:"duration"`
+	Errors   []string  `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
 type TestsXML struct {
 	XMLName    xml.Name       `xml:"testsuites" json:"-" yaml:"-"`
 	TestSuites []TestSuiteXML `xml:"testsuite" json:"test_suites"`
@@ -81,25 +123,34 @@ type Tests struct {
 	Duration         float64     `json:"duration" yaml:"-"`
 	Start            time.Time   `json:"start" yaml:"-"`
 	End              time.Time   `json:"end" yaml:"-"`
+	// the shard of the testsuites run, when they are split across several jobs
+	Shard *Shard `json:"shard,omitempty" yaml:"shard,omitempty"`
 }
 
 // TestSuite is a single JUnit test suite which may contain many
 // testcases.
 type TestSuiteXML struct {
-	XMLName   xml.Name      `xml:"testsuite" json:"-" yaml:"-"`
-	Disabled  int           `xml:"disabled,attr,omitempty" json:"disabled" yaml:""`
-	Errors    int           `xml:"errors,attr,omitempty" json:"errors" yaml:"-"`
-	Failures  int           `xml:"failures,attr,omitempty" json:"failures" yaml:"-"`
-	Hostname  string        `xml:"hostname,attr,omitempty" json:"hostname" yaml:"-"`
-	ID        string        `xml:"id,attr,omitempty" json:"id" yaml:"-"`
-	Name      string        `xml:"name,attr" json:"name" yaml:"name"`
-	Package   string        `xml:"package,attr,omitempty" json:"package" yaml:"-"`
-	Skipped   int           `xml:"skipped,attr,omitempty" json:"skipped" yaml:"skipped,omitempty"`
-	Total     int           `xml:"tests,attr" json:"total" yaml:"total,omitempty"`
-	TestCases []TestCaseXML `xml:"testcase" json:"testcases" yaml:"testcases"`
-	Version   string        `xml:"version,omitempty" json:"version" yaml:"version,omitempty"`
-	Time      string        `xml:"time,attr,omitempty" json:"time" yaml:"-"`
-	Timestamp string        `xml:"timestamp,attr,omitempty" json:"timestamp" yaml:"-"`
+	XMLName    xml.Name      `xml:"testsuite" json:"-" yaml:"-"`
+	Disabled   int           `xml:"disabled,attr,omitempty" json:"disabled" yaml:""`
+	Errors     int           `xml:"errors,attr,omitempty" json:"errors" yaml:"-"`
+	Failures   int           `xml:"failures,attr,omitempty" json:"failures" yaml:"-"`
+	Hostname   string        `xml:"hostname,attr,omitempty" json:"hostname" yaml:"-"`
+	ID         string        `xml:"id,attr,omitempty" json:"id" yaml:"-"`
+	Name       string        `xml:"name,attr" json:"name" yaml:"name"`
+	Package    string        `xml:"package,attr,omitempty" json:"package" yaml:"-"`
+	Properties []PropertyXML `xml:"properties>property,omitempty" json:"properties,omitempty" yaml:"properties,omitempty"`
+	Skipped    int           `xml:"skipped,attr,omitempty" json:"skipped" yaml:"skipped,omitempty"`
+	Total      int           `xml:"tests,attr" json:"total" yaml:"total,omitempty"`
+	TestCases  []TestCaseXML `xml:"testcase" json:"testcases" yaml:"testcases"`
+	Version    string        `xml:"version,omitempty" json:"version" yaml:"version,omitempty"`
+	Time       string        `xml:"time,attr,omitempty" json:"time" yaml:"-"`
+	Timestamp  string        `xml:"timestamp,attr,omitempty" json:"timestamp" yaml:"-"`
+}
+
+// PropertyXML is a JUnit property of a testsuite or a testcase
+type PropertyXML struct {
+	Name  string `xml:"name,attr" json:"name" yaml:"name"`
+	Value string `xml:"value,attr" json:"value" yaml:"value"`
 }
 
 type TestSuiteInput struct {
@@ -108,6 +159,14 @@ type TestSuiteInput struct {
 	TestCases   []TestCaseInput `json:"testcases" yaml:"testcases"`
 	Vars        H               `json:"vars" yaml:"vars"`
 	Secrets     []string        `json:"secrets" yaml:"secrets"`
+	Serial      bool            `json:"serial" yaml:"serial"`
+	Tags        []string        `json:"tags" yaml:"tags"`
+	Include     Includes        `json:"include" yaml:"include"`
+	Timeout     Duration        `json:"timeout" yaml:"timeout"`
+
+	// steps run before and after all the testcases of the suite
+	Setup    []json.RawMessage `json:"setup" yaml:"setup"`
+	Teardown []json.RawMessage `json:"teardown" yaml:"teardown"`
 }
 
 type TestSuite struct {
@@ -116,6 +175,11 @@ type TestSuite struct {
 	TestCases   []TestCase `json:"testcases" yaml:"testcases"`
 	Vars        H          `json:"vars" yaml:"vars"`
 	Secrets     []string   `json:"secrets" yaml:"secrets"`
+	Serial      bool       `json:"serial,omitempty" yaml:"serial,omitempty"`
+	Tags        []string   `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Timeout     Duration   `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Setup       *TestCase  `json:"setup,omitempty" yaml:"setup,omitempty"`
+	Teardown    *TestCase  `json:"teardown,omitempty" yaml:"teardown,omitempty"`
 
 	// computed
 	ShortName    string `json:"shortname" yaml:"-"`
@@ -132,20 +196,53 @@ type TestSuite struct {
 	NbTestcasesFail int `json:"nbTestcasesFail"  yaml:"-"`
 	NbTestcasesPass int `json:"nbTestcasesPass"  yaml:"-"`
 	NbTestcasesSkip int `json:"nbTestcasesSkip"  yaml:"-"`
+	// flaky testcases are also counted as failed
+	NbTestcasesFlaky int `json:"nbTestcasesFlaky,omitempty"  yaml:"-"`
+	// quarantined testcases are not counted as passed or failed
+	NbTestcasesQuarantined int `json:"nbTestcasesQuarantined,omitempty"  yaml:"-"`
+
+	// indexes of the testcases in the order they have to run, according to their dependencies
+	order []int
+}
+
+// allTestCases returns the testcases of the suite surrounded by its setup and teardown
+func (ts TestSuite) allTestCases() []TestCase {
+	testCases := make([]TestCase, 0, len(ts.TestCases)+2)
+	if ts.Setup != nil {
+		testCases = append(testCases, *ts.Setup)
+	}
+	testCases = append(testCases, ts.TestCases...)
+	if ts.Teardown != nil {
+		testCases = append(testCases, *ts.Teardown)
+	}
+	return testCases
+}
+
+// testCaseTags returns the tags of the testcase, including the ones inherited from the testsuite
+func (ts TestSuite) testCaseTags(tc TestCase) []string {
+	tags := make([]string, 0, len(ts.Tags)+len(tc.Tags))
+	tags = append(tags, ts.Tags...)
+	for _, t := range tc.Tags {
+		if !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
 }
 
 // TestCase is a single test case with its result.
 type TestCaseXML struct {
-	XMLName   xml.Name     `xml:"testcase" json:"-" yaml:"-"`
-	Classname string       `xml:"classname,attr,omitempty" json:"classname" yaml:"-"`
-	Errors    []FailureXML `xml:"error,omitempty" json:"errors" yaml:"errors,omitempty"`
-	Failures  []FailureXML `xml:"failure,omitempty" json:"failures" yaml:"failures,omitempty"`
-	Name      string       `xml:"name,attr" json:"name" yaml:"name"`
-	Skipped   []Skipped    `xml:"skipped,omitempty" json:"skipped" yaml:"skipped,omitempty"`
-	Systemout InnerResult  `xml:"system-out,omitempty" json:"systemout" yaml:"systemout,omitempty"`
-	Systemerr InnerResult  `xml:"system-err,omitempty" json:"systemerr" yaml:"systemerr,omitempty"`
-	Time      float64      `xml:"time,attr,omitempty" json:"time" yaml:"time,omitempty"`
-	ID        string       `xml:"id,attr,omitempty" json:"id" yaml:"id"`
+	XMLName    xml.Name      `xml:"testcase" json:"-" yaml:"-"`
+	Classname  string        `xml:"classname,attr,omitempty" json:"classname" yaml:"-"`
+	Errors     []FailureXML  `xml:"error,omitempty" json:"errors" yaml:"errors,omitempty"`
+	Failures   []FailureXML  `xml:"failure,omitempty" json:"failures" yaml:"failures,omitempty"`
+	Name       string        `xml:"name,attr" json:"name" yaml:"name"`
+	Properties []PropertyXML `xml:"properties>property,omitempty" json:"properties,omitempty" yaml:"properties,omitempty"`
+	Skipped    []Skipped     `xml:"skipped,omitempty" json:"skipped" yaml:"skipped,omitempty"`
+	Systemout  InnerResult   `xml:"system-out,omitempty" json:"systemout" yaml:"systemout,omitempty"`
+	Systemerr  InnerResult   `xml:"system-err,omitempty" json:"systemerr" yaml:"systemerr,omitempty"`
+	Time       float64       `xml:"time,attr,omitempty" json:"time" yaml:"time,omitempty"`
+	ID         string        `xml:"id,attr,omitempty" json:"id" yaml:"id"`
 }
 
 type TestCaseInput struct {
@@ -154,6 +251,21 @@ type TestCaseInput struct {
 	Skip         []string          `json:"skip" yaml:"skip"`
 	RawTestSteps []json.RawMessage `json:"steps" yaml:"steps"`
 	ID           string            `json:"id" yaml:"id"`
+	Tags         []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	DependsOn    []string          `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Range        interface{}       `json:"range,omitempty" yaml:"range,omitempty"`
+	Timeout      Duration          `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// steps always run after the steps of the testcase, even if they failed
+	RawFinallySteps []json.RawMessage `json:"finally,omitempty" yaml:"finally,omitempty"`
+}
+
+// testCaseRangeItem is the item of the range of a testcase
+type testCaseRangeItem struct {
+	index int
+	key   string
+	value interface{}
+	label string
 }
 
 type TestCase struct {
@@ -162,8 +274,24 @@ type TestCase struct {
 	// Computed
 	originalName string
 	number       int
-	Skipped      []Skipped `json:"skipped" yaml:"-"`
-	Status       Status    `json:"status" yaml:"-"`
+	dependencies []int
+	// where the steps come from: the testsuite file, included files or the file of a user executor
+	stepOrigins []stepOrigin
+	// the item of the range of the testcase it has been expanded from
+	rangeItem *testCaseRangeItem
+	// the prefix of the variables computed by the testcase
+	varsPrefix string
+	// true if the testcase is rerun because it failed in the previous results
+	rerun         bool
+	Skipped       []Skipped `json:"skipped" yaml:"-"`
+	Status        Status    `json:"status" yaml:"-"`
+	PassedOnRerun bool      `json:"passedOnRerun,omitempty" yaml:"-"`
+	// the position of the testcase in the file it has been read from
+	Position *Position `json:"position,omitempty" yaml:"-"`
+	// the attempts of the testcase, when it is repeated
+	Repeat *TestCaseRepeat `json:"repeat,omitempty" yaml:"-"`
+	// the quarantine of the testcase, when it is a known-flaky one
+	Quarantine *TestCaseQuarantine `json:"quarantine,omitempty" yaml:"-"`
 
 	Duration float64   `json:"duration" yaml:"-"`
 	Start    time.Time `json:"start" yaml:"-"`
@@ -179,6 +307,115 @@ type TestCase struct {
 	IsEvaluated     bool     `json:"-" yaml:"-"`
 }
 
+// allRawTestSteps returns the steps of the testcase followed by its finally steps
+func (tc *TestCase) allRawTestSteps() []json.RawMessage {
+	steps := make([]json.RawMessage, 0, len(tc.RawTestSteps)+len(tc.RawFinallySteps))
+	steps = append(steps, tc.RawTestSteps...)
+	return append(steps, tc.RawFinallySteps...)
+}
+
+// addRangeVars adds the index, key and value variables of the range item of the testcase
+func (tc *TestCase) addRangeVars() {
+	if tc.rangeItem == nil {
+		return
+	}
+	tc.Vars.Add("index", tc.rangeItem.index)
+	tc.Vars.Add("key", tc.rangeItem.key)
+	tc.Vars.Add("value", tc.rangeItem.value)
+}
+
+// computedVarsPrefix returns the prefix of the variables computed by the testcase,
+// which is its name without the range item for a ranged testcase
+func (tc *TestCase) computedVarsPrefix() string {
+	if tc.varsPrefix != "" {
+		return tc.varsPrefix
+	}
+	return tc.Name
+}
+
+// computeStatus computes the counters and the status of the tests from the status of their testsuites
+func (t *Tests) computeStatus() {
+	t.NbTestsuitesFail, t.NbTestsuitesPass, t.NbTestsuitesSkip = 0, 0, 0
+	for _, ts := range t.TestSuites {
+		switch ts.Status {
+		case StatusFail:
+			t.NbTestsuitesFail++
+		case StatusSkip:
+			t.NbTestsuitesSkip++
+		default:
+			t.NbTestsuitesPass++
+		}
+	}
+
+	if t.NbTestsuitesFail > 0 {
+		t.Status = StatusFail
+	} else if t.NbTestsuitesSkip > 0 && t.NbTestsuitesSkip == len(t.TestSuites) {
+		t.Status = StatusSkip
+	} else {
+		t.Status = StatusPass
+	}
+}
+
+// computeStatus computes the counters and the status of the testsuite from the status of its testcases
+func (ts *TestSuite) computeStatus() {
+	var isFailed bool
+	for _, tc := range []*TestCase{ts.Setup, ts.Teardown} {
+		if tc != nil && tc.Status == StatusFail {
+			isFailed = true
+		}
+	}
+
+	ts.NbTestcasesFail, ts.NbTestcasesPass, ts.NbTestcasesSkip, ts.NbTestcasesFlaky, ts.NbTestcasesQuarantined = 0, 0, 0, 0, 0
+	for _, tc := range ts.TestCases {
+		switch tc.Status {
+		case StatusFail:
+			isFailed = true
+			ts.NbTestcasesFail++
+		case StatusFlaky:
+			isFailed = true
+			ts.NbTestcasesFail++
+			ts.NbTestcasesFlaky++
+		case StatusSkip:
+			ts.NbTestcasesSkip++
+		case StatusPass:
+			ts.NbTestcasesPass++
+		case StatusQuarantined:
+			ts.NbTestcasesQuarantined++
+		}
+	}
+
+	if isFailed {
+		ts.Status = StatusFail
+	} else if ts.NbTestcasesSkip > 0 && ts.NbTestcasesSkip == len(ts.TestCases) {
+		ts.Status = StatusSkip
+	} else {
+		ts.Status = StatusPass
+	}
+}
+
+// computeStatus computes the status of the testcase from its steps results
+func (tc *TestCase) computeStatus() {
+	var hasFailure bool
+	skippedSteps := 0
+	for _, testStepResult := range tc.TestStepResults {
+		if testStepResult.Status == StatusFail {
+			hasFailure = true
+		}
+		if testStepResult.Status == StatusSkip {
+			skippedSteps++
+		}
+	}
+
+	if hasFailure {
+		tc.Status = StatusFail
+	} else if skippedSteps == len(tc.TestStepResults) {
+		// If all test steps were skipped, consider the test case as skipped
+		tc.Status = StatusSkip
+	} else if tc.Status != StatusSkip {
+		tc.Status = StatusPass
+	}
+}
+
 type TestStepResult struct {
 	Name              string            `json:"name"`
 	Errors            []Failure         `json:"errors"`
@@ -194,6 +431,13 @@ type TestStepResult struct {
 	ComputedInfo      []string          `json:"computedInfos" yaml:"-"`
 	AssertionsApplied AssertionsApplied `json:"assertionsApplied" yaml:"-"`
 	Retries           int               `json:"retries" yaml:"retries"`
+	UntilAttempts     []UntilAttempt    `json:"untilAttempts,omitempty" yaml:"untilAttempts,omitempty"`
+	Finally           bool              `json:"finally,omitempty" yaml:"finally,omitempty"`
+	Interrupted       bool              `json:"interrupted,omitempty" yaml:"interrupted,omitempty"`
+	// the position of the step in the file it has been read from
+	Position *Position `json:"position,omitempty" yaml:"-"`
+	// the step the executor would have received, in dry-run mode
+	DryRun *DryRunStep `json:"dryRun,omitempty" yaml:"-"`
 
 	Systemout string    `json:"systemout"`
 	Systemerr string    `json:"systemerr"`
@@ -215,6 +459,12 @@ func (ts *TestStepResult) appendFailure(failure ...Failure) {
 // TestStep represents a testStep
 type TestStep map[string]interface{}
 
+// the kinds of failure which can trigger the retry of a step
+const (
+	retryOnError     = "error"
+	retryOnAssertion = "assertion"
+)
+
 func (t TestStep) IntValue(name string) (int, error) {
 	out, err := cast.ToIntE(t[name])
 	if err != nil {
@@ -223,6 +473,101 @@ func (t TestStep) IntValue(name string) (int, error) {
 	return out, nil
 }
 
+// DurationValue returns the value of a duration attribute, written as a number of seconds or as a duration string
+func (t TestStep) DurationValue(name string) (time.Duration, error) {
+	out, err := parseDuration(t[name])
+	if err != nil {
+		return 0, fmt.Errorf("attribute %q is neither a number of seconds nor a duration", name)
+	}
+	return out, nil
+}
+
+// settings returns the attributes of the step handled by venom, whatever its executor
+func (t TestStep) settings() (stepSettings, error) {
+	var s stepSettings
+	var err error
+	if s.retry, err = t.IntValue("retry"); err != nil {
+		return s, err
+	}
+	if s.retryIf, err = t.StringSliceValue("retry_if"); err != nil {
+		return s, err
+	}
+	if s.retryOn, err = t.StringSliceValue("retry_on"); err != nil {
+		return s, err
+	}
+	for _, on := range s.retryOn {
+		if on != retryOnError && on != retryOnAssertion {
+			return s, fmt.Errorf("attribute \"retry_on\" has an invalid value %q: expected %s or %s", on, retryOnError, retryOnAssertion)
+		}
+	}
+	if len(s.retryOn) == 0 {
+		s.retryOn = []string{retryOnError, retryOnAssertion}
+	}
+	if s.retryBackoff, err = t.StringValue("retry_backoff"); err != nil {
+		return s, err
+	}
+	switch s.retryBackoff {
+	case "", "constant", "exponential":
+	default:
+		return s, fmt.Errorf("attribute \"retry_backoff\" has an invalid value %q: expected constant or exponential", s.retryBackoff)
+	}
+	if s.retryJitter, err = t.DurationValue("retry_jitter"); err != nil {
+		return s, err
+	}
+	if s.delay, err = t.DurationValue("delay"); err != nil {
+		return s, err
+	}
+	if s.timeout, err = t.DurationValue("timeout"); err != nil {
+		return s, err
+	}
+	if s.until, err = t.UntilValue(); err != nil {
+		return s, err
+	}
+	s.info, _ = t.StringSliceValue("info")
+	return s, nil
+}
+
+// UntilValue returns the until condition of the step, or nil if the step has none
+func (t TestStep) UntilValue() (*StepUntil, error) {
+	if t["until"] == nil {
+		return nil, nil
+	}
+	btes, err := json.Marshal(t["until"])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid attribute \"until\"")
+	}
+	var until StepUntil
+	if err := json.Unmarshal(btes, &until); err != nil {
+		return nil, errors.Wrapf(err, "invalid attribute \"until\"")
+	}
+	if len(until.Assertions) == 0 {
+		return nil, fmt.Errorf("attribute \"until\" has no assertions")
+	}
+	if until.MaxDuration <= 0 {
+		return nil, fmt.Errorf("attribute \"until\" has no max_duration")
+	}
+	if until.Interval <= 0 {
+		until.Interval = Duration(time.Second)
+	}
+	switch until.Backoff {
+	case "", "constant", "exponential":
+	default:
+		return nil, fmt.Errorf("attribute \"until\" has an invalid backoff %q: expected constant or exponential", until.Backoff)
+	}
+	return &until, nil
+}
+
+// executorName returns the type of the step, exec being the type of the steps with a script or a command and no type
+func (t TestStep) executorName() string {
+	name, _ := t.StringValue("type")
+	script, _ := t.StringValue("script")
+	command, _ := t.StringSliceValue("command")
+	if name == "" && (script != "" || len(command) != 0) {
+		name = "exec"
+	}
+	return name
+}
+
 func (t TestStep) StringValue(name string) (string, error) {
 	out, err := cast.ToStringE(t[name])
 	if err != nil {
@@ -276,6 +621,8 @@ type Failure struct {
 	Error              error  `xml:"-" json:"-" yaml:"-"`
 
 	Value string `json:"value" yaml:"value,omitempty"`
+	// the position of the failed assertion, or of the step
+	Position *Position `xml:"-" json:"position,omitempty" yaml:"-"`
 }
 
 type FailureXML struct {
@@ -284,9 +631,45 @@ type FailureXML struct {
 	Message string `xml:"message,attr,omitempty" json:"message" yaml:"message,omitempty"`
 }
 
-func newFailure(ctx context.Context, tc TestCase, stepNumber int, rangedIndex int, assertion string, err error) *Failure {
+// stepLocation returns the file containing the step, as printed in the failures and the info lines,
+// and the positions of the step
+func (tc TestCase) stepLocation(ctx context.Context, stepNumber int) (string, stepPosition) {
 	filename := StringVarFromCtx(ctx, "venom.testsuite.filename")
-	lineNumber := findLineNumber(filename, tc.originalName, stepNumber, assertion, -1)
+	if stepNumber < 1 || stepNumber > len(tc.stepOrigins) {
+		return filename, stepPosition{}
+	}
+	origin := tc.stepOrigins[stepNumber-1]
+	if origin.filename != "" {
+		filename = origin.filename
+	}
+	return filename, origin.position
+}
+
+// stepPosition returns the position of a step in the file it has been read from, or nil if it is not known
+func (tc TestCase) stepPosition(stepNumber int) *Position {
+	if stepNumber < 1 || stepNumber > len(tc.stepOrigins) {
+		return nil
+	}
+	return tc.stepOrigins[stepNumber-1].position.step
+}
+
+// newFailure returns the failure of a step, located at the step
+func newFailure(ctx context.Context, tc TestCase, stepNumber int, rangedIndex int, assertion string, err error) *Failure {
+	return newAssertionFailure(ctx, tc, stepNumber, rangedIndex, assertion, nil, err)
+}
+
+// newAssertionFailure returns the failure of an assertion of a step, located at the position of the assertion if it is known,
+// at the step otherwise
+func newAssertionFailure(ctx context.Context, tc TestCase, stepNumber int, rangedIndex int, assertion string, position *Position, err error) *Failure {
+	filename, positions := tc.stepLocation(ctx, stepNumber)
+	if position == nil {
+		position = positions.step
+	}
+	var lineNumber int
+	if position != nil {
+		lineNumber = position.Line
+	}
+
 	var value string
 	if assertion != "" {
 		value = fmt.Sprintf(`Testcase %q, step #%d-%d: Assertion %q failed. %s (%v:%d)`,
@@ -317,6 +700,7 @@ func newFailure(ctx context.Context, tc TestCase, stepNumber int, rangedIndex in
 		Assertion:          assertion,
 		Error:              err,
 		Value:              value,
+		Position:           position,
 	}
 
 	return &failure
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/gosimple/slug"
 	"github.com/ovh/venom/interpolate"
@@ -28,8 +29,15 @@ type ExecutorRunner interface {
 	Name() string
 	Retry() int
 	RetryIf() []string
+	RetryOn() []string
+	RetryBackoff() string
+	RetryJitter() time.Duration
+	// Delay and Timeout are in seconds, rounded down: DelayDuration and TimeoutDuration keep the fractions of a second
 	Delay() int
+	DelayDuration() time.Duration
 	Timeout() int
+	TimeoutDuration() time.Duration
+	Until() *StepUntil
 	Info() []string
 	Type() string
 	GetExecutor() Executor
@@ -40,13 +48,22 @@ var _ Executor = new(executor)
 // ExecutorWrap contains an executor implementation and some attributes
 type executor struct {
 	Executor
-	name    string
-	retry   int      // nb retry a test case if it is in failure.
-	retryIf []string // retry conditions to check before performing any retries
-	delay   int      // delay between two retries
-	timeout int      // timeout on executor
-	info    []string // info to display after the run and before the assertion
-	stype   string   // builtin, plugin, user
+	stepSettings
+	name  string
+	stype string // builtin, plugin, user
+}
+
+// stepSettings are the attributes of a step handled by venom, whatever its executor
+type stepSettings struct {
+	retry        int           // nb retry a test case if it is in failure.
+	retryIf      []string      // retry conditions to check before performing any retries
+	retryOn      []string      // kinds of failure triggering a retry: error, assertion
+	retryBackoff string        // constant or exponential
+	retryJitter  time.Duration // maximum random duration added to the delay between two retries
+	delay        time.Duration // delay between two retries
+	timeout      time.Duration // timeout on executor
+	until        *StepUntil    // condition polled before applying the assertions
+	info         []string      // info to display after the run and before the assertion
 }
 
 func (e executor) Name() string {
@@ -65,14 +82,38 @@ func (e executor) RetryIf() []string {
 	return e.retryIf
 }
 
+func (e executor) RetryOn() []string {
+	return e.retryOn
+}
+
+func (e executor) RetryBackoff() string {
+	return e.retryBackoff
+}
+
+func (e executor) RetryJitter() time.Duration {
+	return e.retryJitter
+}
+
 func (e executor) Delay() int {
+	return int(e.delay / time.Second)
+}
+
+func (e executor) DelayDuration() time.Duration {
 	return e.delay
 }
 
 func (e executor) Timeout() int {
+	return int(e.timeout / time.Second)
+}
+
+func (e executor) TimeoutDuration() time.Duration {
 	return e.timeout
 }
 
+func (e executor) Until() *StepUntil {
+	return e.until
+}
+
 func (e executor) Info() []string {
 	return e.info
 }
@@ -132,16 +173,12 @@ func (e executor) Run(ctx context.Context, step TestStep) (interface{}, error) {
 	return e.Executor.Run(ctx, step)
 }
 
-func newExecutorRunner(e Executor, name, stype string, retry int, retryIf []string, delay, timeout int, info []string) ExecutorRunner {
+func newExecutorRunner(e Executor, name, stype string, settings stepSettings) ExecutorRunner {
 	return &executor{
-		Executor: e,
-		name:     name,
-		retry:    retry,
-		retryIf:  retryIf,
-		delay:    delay,
-		timeout:  timeout,
-		info:     info,
-		stype:    stype,
+		Executor:     e,
+		stepSettings: settings,
+		name:         name,
+		stype:        stype,
 	}
 }
 
@@ -176,6 +213,9 @@ type UserExecutor struct {
 	RawInputs []byte            `json:"-" yaml:"-"`
 	Filename  string            `json:"-" yaml:"-"`
 	Output    json.RawMessage   `json:"output" yaml:"output"`
+
+	// where the steps are in the file of the executor
+	stepOrigins []stepOrigin
 }
 
 // Run is not implemented on user executor
@@ -268,6 +308,7 @@ func (v *Venom) RunUserExecutor(ctx context.Context, runner ExecutorRunner, tcIn
 			RawTestSteps: newUX.TestSteps,
 		},
 		number:          tcIn.number,
+		stepOrigins:     ux.stepOrigins,
 		TestSuiteVars:   tcIn.TestSuiteVars,
 		IsExecutor:      true,
 		TestStepResults: make([]TestStepResult, 0),
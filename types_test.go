@@ -1,6 +1,11 @@
 package venom
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
 
 func Test_RemoveNotPrintableChar(t *testing.T) {
 	type args struct {
@@ -26,3 +31,14 @@ func Test_RemoveNotPrintableChar(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutorRunnerDurations(t *testing.T) {
+	settings, err := TestStep{"delay": 2, "timeout": "1500ms"}.settings()
+	require.NoError(t, err)
+	e := newExecutorRunner(nil, "", "builtin", settings)
+	// the seconds of the delay and of the timeout are rounded down
+	require.Equal(t, 2, e.Delay())
+	require.Equal(t, 2*time.Second, e.DelayDuration())
+	require.Equal(t, 1, e.Timeout())
+	require.Equal(t, 1500*time.Millisecond, e.TimeoutDuration())
+}
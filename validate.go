@@ -0,0 +1,500 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gosimple/slug"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ovh/venom/assertions"
+)
+
+// Diagnostic is a problem found by Validate in a testsuite or a user executor.
+// Line and Column are zero when the position of the problem is unknown.
+type Diagnostic struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.File, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Message)
+}
+
+// stepKeys are the attributes of a step handled by venom, whatever its executor
+var stepKeys = []string{
+	"type", "name", "assertions", "info", "vars", "extracts", "range", "skip", "until",
+	"retry", "retry_if", "retry_on", "retry_backoff", "retry_jitter", "delay", "timeout",
+}
+
+// builtinVariables are the first part of the variables always defined while running a step
+var builtinVariables = []string{"venom", "value", "index", "key", "input", "result", "setup", "teardown"}
+
+var (
+	templateRegex       = regexp.MustCompile(`{{(.*?)}}`)
+	templateVarRegex    = regexp.MustCompile(`^\s*-?\s*\.([\w\-]+(?:\.[\w\-]+)*)`)
+	yamlErrorLineRegex  = regexp.MustCompile(`line (\d+)`)
+	templateErrorRegex  = regexp.MustCompile(`template: \w+:(\d+)(?::(\d+))?`)
+	userExecutorSection = []string{"executor", "input", "steps", "output"}
+)
+
+// Validate checks the testsuites of paths and the user executors of the lib directories, without running them:
+// the executors of the steps and their attributes, the assertions and the variables used by the steps.
+func (v *Venom) Validate(ctx context.Context, paths []string) ([]Diagnostic, error) {
+	filesPath, err := getFilesPath(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	val := &validator{v: v}
+	executorFiles := val.readUserExecutors(ctx)
+	for _, f := range executorFiles {
+		val.file = f.path
+		for _, step := range sequenceItems(mappingValue(f.root, "steps")) {
+			val.checkStep(step, nil)
+		}
+	}
+	for _, f := range filesPath {
+		// the user executors given as paths are already checked
+		if abs, err := filepath.Abs(f); err == nil && val.executorFiles[abs] {
+			continue
+		}
+		val.checkTestSuite(ctx, f)
+	}
+
+	sort.SliceStable(val.diagnostics, func(i, j int) bool {
+		a, b := val.diagnostics[i], val.diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return val.diagnostics, nil
+}
+
+type validator struct {
+	v           *Venom
+	file        string
+	diagnostics []Diagnostic
+	// executorFiles are the absolute paths of the files of the user executors
+	executorFiles map[string]bool
+}
+
+type userExecutorFile struct {
+	path string
+	root *yaml.Node
+}
+
+// report adds a diagnostic at the position of the node n, or without position if n is nil
+func (val *validator) report(n *yaml.Node, format string, args ...interface{}) {
+	d := Diagnostic{File: val.file, Message: fmt.Sprintf(format, args...)}
+	if n != nil {
+		d.Line, d.Column = n.Line, n.Column
+	}
+	val.diagnostics = append(val.diagnostics, d)
+}
+
+// reportError adds a diagnostic for an error of the yaml parser or of the interpolation,
+// at the position found in its message
+func (val *validator) reportError(err error) {
+	d := Diagnostic{File: val.file, Message: err.Error()}
+	if m := templateErrorRegex.FindStringSubmatch(d.Message); m != nil {
+		d.Line, _ = strconv.Atoi(m[1])
+		d.Column, _ = strconv.Atoi(m[2])
+	} else if m := yamlErrorLineRegex.FindStringSubmatch(d.Message); m != nil {
+		d.Line, _ = strconv.Atoi(m[1])
+	}
+	if d.Line > 0 && d.Column == 0 {
+		d.Column = 1
+	}
+	val.diagnostics = append(val.diagnostics, d)
+}
+
+// parse returns the root mapping of a yaml document, or nil if it is invalid.
+// The values starting with a template expression are quoted as venom run does, they are strings once interpolated.
+func (val *validator) parse(content []byte) *yaml.Node {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(quoteTemplateExpressions(content), &doc); err != nil {
+		val.reportError(err)
+		return nil
+	}
+	if len(doc.Content) == 0 {
+		val.report(nil, "empty file")
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		val.report(root, "expected a map")
+		return nil
+	}
+	return root
+}
+
+// readUserExecutors registers the user executors, and returns their files to check their steps
+func (val *validator) readUserExecutors(ctx context.Context) []userExecutorFile {
+	vars, err := DumpStringPreserveCase(val.v.variables)
+	if err != nil {
+		val.report(nil, "unable to parse variables: %v", err)
+		return nil
+	}
+
+	var files []userExecutorFile
+	val.executorFiles = map[string]bool{}
+	for _, f := range val.v.getUserExecutorFilesPath(ctx, vars) {
+		val.file = f
+		val.executorFiles[f] = true
+		ux, err := readUserExecutor(f)
+		if err != nil {
+			val.report(nil, "%v", err)
+			continue
+		}
+		root := val.parse(ux.Raw)
+		if root == nil {
+			continue
+		}
+		val.checkKeys(root, userExecutorSection, "user executor")
+		if err := val.v.RegisterExecutorUser(ux.Executor, ux); err != nil {
+			val.report(mappingValue(root, "executor"), "%v", err)
+			continue
+		}
+		files = append(files, userExecutorFile{path: f, root: root})
+	}
+	return files
+}
+
+func (val *validator) checkTestSuite(ctx context.Context, filePath string) {
+	val.file = filePath
+	btes, err := os.ReadFile(filePath)
+	if err != nil {
+		val.report(nil, "unable to read file: %v", err)
+		return
+	}
+
+	vars := val.v.variables.Clone()
+	content, err := interpolateFile(ctx, filePath, btes, vars)
+	if err != nil {
+		val.reportError(err)
+		return
+	}
+	root := val.parse([]byte(content))
+	if root == nil {
+		return
+	}
+	val.checkKeys(root, jsonFields(TestSuiteInput{}), "testsuite")
+
+	// the variables of the included files are not known
+	var known map[string]struct{}
+	if mappingValue(root, "include") == nil {
+		known = knownVariables(root, vars)
+	}
+
+	for _, name := range []string{"setup", "teardown"} {
+		for _, step := range sequenceItems(mappingValue(root, name)) {
+			val.checkStep(step, known)
+		}
+	}
+	for _, tc := range sequenceItems(mappingValue(root, "testcases")) {
+		if tc.Kind != yaml.MappingNode {
+			val.report(tc, "expected a testcase")
+			continue
+		}
+		val.checkKeys(tc, jsonFields(TestCaseInput{}), "testcase")
+		for _, condition := range sequenceItems(mappingValue(tc, "skip")) {
+			val.checkAssertion(condition)
+		}
+		for _, name := range []string{"steps", "finally"} {
+			for _, step := range sequenceItems(mappingValue(tc, name)) {
+				val.checkStep(step, known)
+			}
+		}
+	}
+}
+
+// checkKeys reports the keys of the mapping n which are not in keys
+func (val *validator) checkKeys(n *yaml.Node, keys []string, what string) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := n.Content[i]
+		if !containsFold(keys, key.Value) {
+			val.report(key, "unknown field %q of the %s", key.Value, what)
+		}
+	}
+}
+
+// checkStep checks the executor of a step, its attributes, its assertions and its variables.
+// The variables are not checked if known is nil.
+func (val *validator) checkStep(n *yaml.Node, known map[string]struct{}) {
+	if n.Kind != yaml.MappingNode {
+		val.report(n, "expected a step")
+		return
+	}
+	// the included steps are checked with the file including them
+	if mappingValue(n, "include") != nil {
+		return
+	}
+
+	var step TestStep
+	if err := n.Decode(&step); err != nil {
+		val.reportError(err)
+		return
+	}
+	if _, err := step.settings(); err != nil {
+		val.report(n, "%v", err)
+	}
+
+	name := step.executorName()
+	if name != "" && !strings.Contains(name, "{{") {
+		fields, found := val.executorFields(name)
+		if !found {
+			val.report(mappingValue(n, "type"), "unknown executor %q", name)
+		} else if fields != nil {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key := n.Content[i]
+				if !containsFold(stepKeys, key.Value) && !containsFold(fields, key.Value) {
+					val.report(key, "unknown field %q for executor %q", key.Value, name)
+				}
+			}
+		}
+	}
+
+	for _, key := range []string{"assertions", "retry_if", "skip"} {
+		for _, a := range sequenceItems(mappingValue(n, key)) {
+			val.checkAssertion(a)
+		}
+	}
+	for _, a := range sequenceItems(mappingValue(mappingValue(n, "until"), "assertions")) {
+		val.checkAssertion(a)
+	}
+
+	if known != nil {
+		val.checkVariables(n, known)
+	}
+}
+
+// executorFields returns the attributes of the executor, or nil if they are not known.
+// found is false if the executor is neither a builtin, a user executor nor a plugin.
+func (val *validator) executorFields(name string) ([]string, bool) {
+	if e, ok := val.v.executorsBuiltin[name]; ok {
+		return structFields(reflect.TypeOf(e)), true
+	}
+	if e, ok := val.v.executorsUser[name]; ok {
+		var inputs struct {
+			Input map[string]interface{} `yaml:"input"`
+		}
+		if err := yaml.Unmarshal(quoteTemplateExpressions(e.(UserExecutor).RawInputs), &inputs); err != nil {
+			return nil, true
+		}
+		fields := []string{}
+		for k := range inputs.Input {
+			fields = append(fields, k)
+		}
+		return fields, true
+	}
+	if _, ok := val.v.executorsPlugin[name]; ok {
+		return nil, true
+	}
+
+	// the plugins are loaded from the lib directory of the testsuite, or from the lib directory of venom
+	dirs := []string{"lib"}
+	if val.file != "" {
+		dirs = append(dirs, filepath.Join(filepath.Dir(val.file), "lib"))
+	}
+	for _, dir := range dirs {
+		if fi, err := os.Stat(filepath.Join(dir, name+".so")); err == nil && !fi.IsDir() {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// checkAssertion checks the operator of an assertion and its number of values.
+// The branches of the logical operators are checked recursively.
+func (val *validator) checkAssertion(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			val.checkAssertion(n.Content[i])
+		}
+		return
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			val.checkAssertion(item)
+		}
+		return
+	case yaml.ScalarNode:
+	default:
+		val.report(n, "expected an assertion")
+		return
+	}
+
+	parts := splitAssertion(n.Value)
+	if len(parts) < 2 {
+		val.report(n, "invalid assertion %q: expected a value followed by an operator", n.Value)
+		return
+	}
+	operator := parts[1]
+	if strings.HasPrefix(operator, "Must") {
+		operator = strings.Replace(operator, "Must", "Should", 1)
+	}
+	// the operator is only known once interpolated
+	if strings.Contains(operator, "{{") {
+		return
+	}
+	if _, ok := assertions.Get(operator); !ok {
+		val.report(n, "unknown assertion operator %q", parts[1])
+		return
+	}
+	min, max, ok := assertions.Arity(operator)
+	if !ok {
+		return
+	}
+	if got := len(parts) - 2; got < min || (max >= 0 && got > max) {
+		expected := fmt.Sprintf("exactly %d", min)
+		if max < 0 {
+			expected = fmt.Sprintf("at least %d", min)
+		}
+		val.report(n, "assertion %s expects %s value(s), got %d", parts[1], expected, got)
+	}
+}
+
+// checkVariables reports the variables used in the step which are neither defined nor computed by the steps
+func (val *validator) checkVariables(n *yaml.Node, known map[string]struct{}) {
+	if n.Kind == yaml.ScalarNode {
+		for _, m := range templateRegex.FindAllStringSubmatch(n.Value, -1) {
+			// a default value is used for the undefined variables
+			if strings.Contains(m[1], "default") {
+				continue
+			}
+			name := templateVarRegex.FindStringSubmatch(m[1])
+			if name == nil {
+				continue
+			}
+			if _, ok := known[name[1]]; ok {
+				continue
+			}
+			if _, ok := known[strings.Split(name[1], ".")[0]]; ok {
+				continue
+			}
+			val.report(n, "unresolved variable %q", name[1])
+		}
+		return
+	}
+	for _, c := range n.Content {
+		val.checkVariables(c, known)
+	}
+}
+
+// knownVariables returns the variables which can be used by the steps of a testsuite: the variables of the
+// testsuite and the global ones, the builtin variables, and the names of the testcases and of the variables
+// extracted from the results of the steps, which prefix the variables they compute
+func knownVariables(root *yaml.Node, vars H) map[string]struct{} {
+	known := map[string]struct{}{}
+	for _, name := range builtinVariables {
+		known[name] = struct{}{}
+	}
+	for k := range vars {
+		known[k] = struct{}{}
+		known[strings.Split(k, ".")[0]] = struct{}{}
+	}
+	for k := range mappingKeys(mappingValue(root, "vars")) {
+		known[k] = struct{}{}
+	}
+
+	var steps []*yaml.Node
+	for _, name := range []string{"setup", "teardown"} {
+		steps = append(steps, sequenceItems(mappingValue(root, name))...)
+	}
+	for _, tc := range sequenceItems(mappingValue(root, "testcases")) {
+		if name := mappingValue(tc, "name"); name != nil {
+			known[name.Value] = struct{}{}
+			known[slug.Make(name.Value)] = struct{}{}
+		}
+		for k := range mappingKeys(mappingValue(tc, "vars")) {
+			known[k] = struct{}{}
+		}
+		for _, name := range []string{"steps", "finally"} {
+			steps = append(steps, sequenceItems(mappingValue(tc, name))...)
+		}
+	}
+	for _, step := range steps {
+		for _, name := range []string{"vars", "extracts"} {
+			for k := range mappingKeys(mappingValue(step, name)) {
+				known[k] = struct{}{}
+			}
+		}
+	}
+	return known
+}
+
+// structFields returns the names of the attributes decoded into the executor type t:
+// the names of its fields, and their mapstructure and json names
+func structFields(t reflect.Type) []string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			fields = append(fields, structFields(f.Type)...)
+			continue
+		}
+		fields = append(fields, f.Name)
+		for _, tag := range []string{"mapstructure", "json", "yaml"} {
+			if name := strings.Split(f.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+				fields = append(fields, name)
+			}
+		}
+	}
+	return fields
+}
+
+// jsonFields returns the json names of the fields of a struct
+func jsonFields(i interface{}) []string {
+	var fields []string
+	t := reflect.TypeOf(i)
+	for i := 0; i < t.NumField(); i++ {
+		if name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func containsFold(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// mappingKeys returns the keys of the mapping n
+func mappingKeys(n *yaml.Node) map[string]struct{} {
+	keys := map[string]struct{}{}
+	if n == nil || n.Kind != yaml.MappingNode {
+		return keys
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keys[n.Content[i].Value] = struct{}{}
+	}
+	return keys
+}
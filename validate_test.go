@@ -0,0 +1,107 @@
+package venom
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// valueExecutor has a single value attribute, to check the attributes of the steps
+type valueExecutor struct {
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+func (valueExecutor) Run(ctx context.Context, step TestStep) (interface{}, error) {
+	return nil, nil
+}
+
+func TestValidate(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"suite.yml": `name: suite
+vars:
+  url: http://localhost
+testcases:
+- name: first
+  steps:
+  - type: value
+    value: '{{.url}} {{.undefined}} {{.other | default "x"}}'
+    valeu: typo
+    assertions:
+    - result.value ShouldEqual
+    - result.value ShouldBeNil 1
+    - result.value ShouldBeFoo 1
+    - or:
+      - result.value ShouldNotBeEmpty
+      - result.value
+    vars:
+      computed:
+        from: result.value
+  - type: unknown
+- name: second
+  steps:
+  - type: user
+    input: '{{.computed}} {{.first.computed}}'
+    bar: baz
+  foo: bar
+`,
+		"invalid.yml": "name: invalid\ntestcases:\n- name: [\n",
+		"lib/user.yml": `executor: user
+input:
+  input: {}
+steps:
+- type: value
+  value: '{{.input.input}}'
+  assertions:
+  - result.value ShouldMatchRegex
+`,
+	})
+
+	v := newTestVenom(t, map[string]Executor{"value": valueExecutor{}})
+	v.LibDir = filepath.Join(dir, "lib")
+	diagnostics, err := v.Validate(context.Background(), []string{filepath.Join(dir, "suite.yml"), filepath.Join(dir, "invalid.yml")})
+	require.NoError(t, err)
+
+	var got []string
+	for _, d := range diagnostics {
+		rel, err := filepath.Rel(dir, d.File)
+		require.NoError(t, err)
+		d.File = rel
+		got = append(got, d.String())
+	}
+	require.Equal(t, []string{
+		"invalid.yml:3:1: yaml: line 3: did not find expected node content",
+		`lib/user.yml:8:5: assertion ShouldMatchRegex expects exactly 1 value(s), got 0`,
+		`suite.yml:8:12: unresolved variable "undefined"`,
+		`suite.yml:9:5: unknown field "valeu" for executor "value"`,
+		`suite.yml:11:7: assertion ShouldEqual expects at least 1 value(s), got 0`,
+		`suite.yml:12:7: assertion ShouldBeNil expects exactly 0 value(s), got 1`,
+		`suite.yml:13:7: unknown assertion operator "ShouldBeFoo"`,
+		`suite.yml:16:9: invalid assertion "result.value": expected a value followed by an operator`,
+		`suite.yml:20:11: unknown executor "unknown"`,
+		`suite.yml:25:5: unknown field "bar" for executor "user"`,
+		`suite.yml:26:3: unknown field "foo" of the testcase`,
+	}, got)
+}
+
+func TestValidateNoProblem(t *testing.T) {
+	dir := writeTestSuites(t, `name: suite
+testcases:
+- name: echo
+  steps:
+  - type: value
+    value: '{{.venom.testsuite}} {{.value}}'
+    range: [a, b]
+    assertions:
+    - result.value ShouldNotBeEmpty
+    - result.value MustContainSubstring a b
+  - type: value
+    value: {{.venom.testsuite}} of {{.value}} in a suite
+    range: [a, b]
+`)
+	v := newTestVenom(t, map[string]Executor{"value": valueExecutor{}})
+	diagnostics, err := v.Validate(context.Background(), []string{dir})
+	require.NoError(t, err)
+	require.Empty(t, diagnostics)
+}
@@ -11,8 +11,11 @@ import (
 	"path/filepath"
 	"plugin"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/confluentinc/bincover"
 	"github.com/fatih/color"
@@ -44,13 +47,13 @@ type ContextKey string
 func New() *Venom {
 	v := &Venom{
 		LogOutput:        os.Stdout,
-		PrintFunc:        fmt.Printf,
 		executorsBuiltin: map[string]Executor{},
 		executorsPlugin:  map[string]Executor{},
 		executorsUser:    map[string]Executor{},
 		variables:        map[string]interface{}{},
 		secrets:          map[string]interface{}{},
 		OutputFormat:     "xml",
+		mutex:            &sync.Mutex{},
 	}
 	return v
 }
@@ -58,6 +61,7 @@ func New() *Venom {
 type Venom struct {
 	LogOutput io.Writer
 
+	// PrintFunc prints the progress of the run and the written reports, they are printed into Output if nil
 	PrintFunc        func(format string, a ...interface{}) (n int, err error)
 	executorsBuiltin map[string]Executor
 	executorsPlugin  map[string]Executor
@@ -73,16 +77,83 @@ type Venom struct {
 	StopOnFailure bool
 	HtmlReport    bool
 	Verbose       int
+	Parallel      int
+	RunFilter     string
+	Tags          string
+	ExcludeTags   string
+	// Timeout of the whole run, no timeout if zero
+	Timeout time.Duration
+	// AggregateReport writes a report of all the testsuites per format of OutputFormat,
+	// a comma separated list of formats such as "xml,json", in addition to the report of each testsuite
+	AggregateReport bool
+	// RerunFailed is the json report, or the directory of the json reports, of a previous run
+	// whose failed testcases are rerun
+	RerunFailed string
+	// RerunMerge merges the results of the rerun into the previous results
+	RerunMerge bool
+	// Repeat is the number of times each testcase is run, to detect the flaky ones
+	Repeat int
+	// RepeatUntilFail stops repeating a testcase at its first failure
+	RepeatUntilFail bool
+	// Quarantine lists the testcases which are run, but whose failures do not fail the run
+	Quarantine []Quarantine
+	// QuarantineFile is the file Quarantine has been read from, it is not read as a testsuite
+	QuarantineFile string
+	// Shard is the part of the testsuites to run, all of them if nil
+	Shard *Shard
+	// ShardTimings is the json report, or the directory of the json reports, of a previous run
+	// used to balance the shards by the durations of the testsuites
+	ShardTimings string
+	// Events receives the events of the run as newline-delimited json, no event is written if nil
+	Events io.Writer
+	// Output receives the progress of the run and the written reports when PrintFunc is nil, nothing is printed if both are nil
+	Output io.Writer
+	// DryRun interpolates the steps without running their executors,
+	// and records the steps the executors would have received in the results of the steps
+	DryRun bool
+
+	// compiled from RunFilter, Tags and ExcludeTags when parsing the testsuites
+	runFilter         *regexp.Regexp
+	tagsFilter        tagExpression
+	excludeTagsFilter tagExpression
+	quarantine        []quarantineRule
+	// read from RerunFailed when parsing the testsuites
+	previous *previousResults
+	hooks    []Hooks
+	// writes into Events while processing the testsuites
+	events *eventWriter
+
+	// mutex is shared between the copies of venom used to run testsuites in parallel
+	mutex *sync.Mutex
 }
 
 var trace = color.New(color.Attribute(90)).SprintFunc()
 
 func (v *Venom) Print(format string, a ...interface{}) {
+	if v.PrintFunc == nil {
+		if v.Output != nil {
+			fmt.Fprintf(v.Output, format, a...) // nolint
+		}
+		return
+	}
 	v.PrintFunc(format, a...) // nolint
 }
 
 func (v *Venom) Println(format string, a ...interface{}) {
-	v.PrintFunc(format+"\n", a...) // nolint
+	v.Print(format+"\n", a...)
+}
+
+// withOutput returns a copy of venom sharing the same executors and variables,
+// but printing its output into w
+func (v *Venom) withOutput(w io.Writer) *Venom {
+	// the counters of the tests may be updated by the other testsuites while copying
+	v.mutex.Lock()
+	c := *v
+	v.mutex.Unlock()
+	c.PrintFunc = func(format string, a ...interface{}) (int, error) {
+		return fmt.Fprintf(w, format, a...)
+	}
+	return &c
 }
 
 func (v *Venom) PrintlnTrace(s string) {
@@ -115,9 +186,14 @@ func (v *Venom) RegisterExecutorPlugin(name string, e Executor) {
 	v.executorsPlugin[name] = e
 }
 
-// RegisterExecutorUser registers an user executor
+// RegisterExecutorUser registers an user executor.
+// An executor registered again from the same file, by a later run, replaces the previous one.
 func (v *Venom) RegisterExecutorUser(name string, e Executor) error {
 	if existing, ok := v.executorsUser[name]; ok {
+		if ux, ok := e.(UserExecutor); ok && ux.Filename == existing.(UserExecutor).Filename {
+			v.executorsUser[name] = e
+			return nil
+		}
 		return fmt.Errorf("executor %q already exists (from file %q)", name, existing.(UserExecutor).Filename)
 	}
 
@@ -128,30 +204,12 @@ func (v *Venom) RegisterExecutorUser(name string, e Executor) error {
 // GetExecutorRunner initializes a test according to its type
 // if no type is provided, exec is default
 func (v *Venom) GetExecutorRunner(ctx context.Context, ts TestStep, h H) (context.Context, ExecutorRunner, error) {
-	name, _ := ts.StringValue("type")
-	script, _ := ts.StringValue("script")
-	command, _ := ts.StringSliceValue("command")
-	if name == "" && (script != "" || len(command) != 0) {
-		name = "exec"
-	}
-	retry, err := ts.IntValue("retry")
-	if err != nil {
-		return nil, nil, err
-	}
-	retryIf, err := ts.StringSliceValue("retry_if")
-	if err != nil {
-		return nil, nil, err
-	}
-	delay, err := ts.IntValue("delay")
-	if err != nil {
-		return nil, nil, err
-	}
-	timeout, err := ts.IntValue("timeout")
+	name := ts.executorName()
+	settings, err := ts.settings()
 	if err != nil {
 		return nil, nil, err
 	}
 
-	info, _ := ts.StringSliceValue("info")
 	vars, err := DumpStringPreserveCase(h)
 	if err != nil {
 		return ctx, nil, err
@@ -165,60 +223,37 @@ func (v *Venom) GetExecutorRunner(ctx context.Context, ts TestStep, h H) (contex
 	ctx = context.WithValue(ctx, ContextKey("vars"), allKeys)
 
 	if name == "" {
-		return ctx, newExecutorRunner(nil, name, "builtin", retry, retryIf, delay, timeout, info), nil
+		return ctx, newExecutorRunner(nil, name, "builtin", settings), nil
 	}
 
 	if ex, ok := v.executorsBuiltin[name]; ok {
-		return ctx, newExecutorRunner(ex, name, "builtin", retry, retryIf, delay, timeout, info), nil
+		return ctx, newExecutorRunner(ex, name, "builtin", settings), nil
 	}
 
 	if ex, ok := v.executorsUser[name]; ok {
-		return ctx, newExecutorRunner(ex, name, "user", retry, retryIf, delay, timeout, info), nil
+		return ctx, newExecutorRunner(ex, name, "user", settings), nil
 	}
 
-	if err := v.registerPlugin(ctx, name, vars); err != nil {
-		Debug(ctx, "executor %q is not implemented as plugin - err:%v", name, err)
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if _, ok := v.executorsPlugin[name]; !ok {
+		if err := v.registerPlugin(ctx, name, vars); err != nil {
+			Debug(ctx, "executor %q is not implemented as plugin - err:%v", name, err)
+		}
 	}
 
 	// then add the executor plugin to the map to not have to load it on each step
 	if ex, ok := v.executorsPlugin[name]; ok {
-		return ctx, newExecutorRunner(ex, name, "plugin", retry, retryIf, delay, timeout, info), nil
+		return ctx, newExecutorRunner(ex, name, "plugin", settings), nil
 	}
 	return ctx, nil, fmt.Errorf("user executor %q not found - loaded executors are: %v", name, reflect.ValueOf(v.executorsUser).MapKeys())
 }
 
 func (v *Venom) getUserExecutorFilesPath(ctx context.Context, vars map[string]string) []string {
-	var libpaths []string
-	// ensure libpaths is unique
-	seen := make(map[string]struct{})
-
-	if v.LibDir != "" {
-		for _, lp := range strings.Split(v.LibDir, string(os.PathListSeparator)) {
-			abs := strings.TrimSpace(lp)
-			if abs == "" {
-				continue
-			}
-			absPath, err := filepath.Abs(abs)
-			if err == nil {
-				seen[absPath] = struct{}{}
-				libpaths = append(libpaths, absPath)
-			}
-		}
-	}
-
-	relLib := path.Join(vars["venom.testsuite.workdir"], "lib")
-	if absRelLib, err := filepath.Abs(relLib); err == nil {
-		if _, exists := seen[absRelLib]; !exists {
-			libpaths = append(libpaths, absRelLib)
-			seen[absRelLib] = struct{}{}
-		}
-	}
 	//use a map to avoid duplicates
 	filepaths := make(map[string]bool)
 
-	for _, p := range libpaths {
-		p = strings.TrimSpace(p)
-
+	for _, p := range v.libDirs(vars) {
 		err := filepath.Walk(p, func(fp string, f os.FileInfo, err error) error {
 			switch ext := filepath.Ext(fp); ext {
 			case ".yml", ".yaml":
@@ -245,6 +280,36 @@ func (v *Venom) getUserExecutorFilesPath(ctx context.Context, vars map[string]st
 	return userExecutorFiles
 }
 
+// libDirs returns the absolute paths of the lib directories: the ones of LibDir, then the lib directory of the testsuite workdir
+func (v *Venom) libDirs(vars map[string]string) []string {
+	var libpaths []string
+	// ensure libpaths is unique
+	seen := make(map[string]struct{})
+
+	if v.LibDir != "" {
+		for _, lp := range strings.Split(v.LibDir, string(os.PathListSeparator)) {
+			abs := strings.TrimSpace(lp)
+			if abs == "" {
+				continue
+			}
+			absPath, err := filepath.Abs(abs)
+			if err == nil {
+				seen[absPath] = struct{}{}
+				libpaths = append(libpaths, absPath)
+			}
+		}
+	}
+
+	relLib := path.Join(vars["venom.testsuite.workdir"], "lib")
+	if absRelLib, err := filepath.Abs(relLib); err == nil {
+		if _, exists := seen[absRelLib]; !exists {
+			libpaths = append(libpaths, absRelLib)
+			seen[absRelLib] = struct{}{}
+		}
+	}
+	return libpaths
+}
+
 func (v *Venom) registerUserExecutors(ctx context.Context) error {
 	vars, err := DumpStringPreserveCase(v.variables)
 	if err != nil {
@@ -255,26 +320,9 @@ func (v *Venom) registerUserExecutors(ctx context.Context) error {
 
 	for _, f := range executorsPath {
 		Info(ctx, "Reading %v", f)
-		content, err := os.ReadFile(f)
+		ux, err := readUserExecutor(f)
 		if err != nil {
-			return errors.Wrapf(err, "unable to read file %q", f)
-		}
-
-		ex := readPartialYML(content, "executor")
-		if len(ex) == 0 {
-			return errors.Errorf("missing key 'executor' in %q", f)
-		}
-
-		name := strings.Replace(ex, "executor:", "", 1)
-		name = strings.TrimSpace(name)
-
-		inputs := readPartialYML(content, "input")
-
-		ux := UserExecutor{
-			Filename:  f,
-			Executor:  name,
-			RawInputs: []byte(inputs),
-			Raw:       content,
+			return err
 		}
 
 		err = v.RegisterExecutorUser(ux.Executor, ux)
@@ -287,6 +335,39 @@ func (v *Venom) registerUserExecutors(ctx context.Context) error {
 	return nil
 }
 
+// readUserExecutor reads the user executor defined in the file f
+func readUserExecutor(f string) (UserExecutor, error) {
+	content, err := os.ReadFile(f)
+	if err != nil {
+		return UserExecutor{}, errors.Wrapf(err, "unable to read file %q", f)
+	}
+
+	ex := readPartialYML(content, "executor")
+	if len(ex) == 0 {
+		return UserExecutor{}, errors.Errorf("missing key 'executor' in %q", f)
+	}
+
+	name := strings.Replace(ex, "executor:", "", 1)
+	name = strings.TrimSpace(name)
+
+	inputs := readPartialYML(content, "input")
+
+	// the steps are located in the file, to report where they fail
+	var origins []stepOrigin
+	root := readPositions(content, quoteTemplateExpressions(content))
+	for _, n := range sequenceItems(mappingValue(root, "steps")) {
+		origins = append(origins, stepOrigin{filename: f, position: newStepPosition(f, n)})
+	}
+
+	return UserExecutor{
+		Filename:    f,
+		Executor:    name,
+		RawInputs:   []byte(inputs),
+		Raw:         content,
+		stepOrigins: origins,
+	}, nil
+}
+
 func (v *Venom) registerPlugin(ctx context.Context, name string, vars map[string]string) error {
 	workdir := vars["venom.testsuite.workdir"]
 	// try to load from testsuite path
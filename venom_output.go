@@ -3,17 +3,16 @@ package venom
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/fatih/color"
 	tap "github.com/mndrix/tap-go"
 	"github.com/pkg/errors"
-	yaml "gopkg.in/yaml.v2"
 )
 
 func init() {
@@ -25,7 +24,7 @@ func init() {
 
 // CleanUpSecrets This method tries to hide all the sensitive variables
 func (v *Venom) CleanUpSecrets(testSuite TestSuite) TestSuite {
-	for _, testCase := range testSuite.TestCases {
+	for _, testCase := range testSuite.allTestCases() {
 		ctx := v.processSecrets(context.Background(), &testSuite, &testCase)
 		for _, result := range testCase.TestStepResults {
 			for k, v := range result.ComputedVars {
@@ -57,6 +56,11 @@ func (v *Venom) OutputResult() error {
 	if v.OutputDir == "" {
 		return nil
 	}
+	formats, err := v.outputFormats()
+	if err != nil {
+		return err
+	}
+
 	cleanedTs := []TestSuite{}
 	for i := range v.Tests.TestSuites {
 		tcFiltered := []TestCase{}
@@ -66,74 +70,36 @@ func (v *Venom) OutputResult() error {
 			}
 		}
 		v.Tests.TestSuites[i].TestCases = tcFiltered
-		ts := v.CleanUpSecrets(v.Tests.TestSuites[i])
-		cleanedTs = append(cleanedTs, ts)
-
-		testsResult := &Tests{
-			TestSuites:       []TestSuite{ts},
-			Status:           v.Tests.Status,
-			NbTestsuitesFail: v.Tests.NbTestsuitesFail,
-			NbTestsuitesPass: v.Tests.NbTestsuitesPass,
-			NbTestsuitesSkip: v.Tests.NbTestsuitesSkip,
-			Duration:         v.Tests.Duration,
-			Start:            v.Tests.Start,
-			End:              v.Tests.End,
-		}
-
-		var data []byte
-		var err error
+		cleanedTs = append(cleanedTs, v.CleanUpSecrets(v.Tests.TestSuites[i]))
+	}
 
-		switch v.OutputFormat {
-		case "json":
-			data, err = json.MarshalIndent(testsResult, "", "  ")
-			if err != nil {
-				return errors.Wrapf(err, "Error: cannot format output json (%s)", err)
-			}
-		case "tap":
-			data, err = outputTapFormat(*testsResult)
-			if err != nil {
-				return errors.Wrapf(err, "Error: cannot format output tap (%s)", err)
-			}
-		case "yml", "yaml":
-			data, err = yaml.Marshal(testsResult)
-			if err != nil {
-				return errors.Wrapf(err, "Error: cannot format output yaml (%s)", err)
-			}
-		case "xml":
-			data, err = outputXMLFormat(*testsResult, v.Verbose)
-			if err != nil {
-				return errors.Wrapf(err, "Error: cannot format output xml (%s)", err)
+	// one file per testsuite and per format
+	for _, ts := range cleanedTs {
+		testsResult := v.testsResult([]TestSuite{ts})
+		fname := strings.TrimSuffix(filepath.Base(ts.Filepath), filepath.Ext(ts.Filepath))
+		for _, format := range formats {
+			if err := v.writeReport(testsResult, format, filepath.Join(v.OutputDir, "test_results_"+fname+"."+format)); err != nil {
+				return err
 			}
-		case "html":
-			return errors.New("Error: you have to use the --html-report flag")
 		}
+	}
 
-		fname := strings.TrimSuffix(filepath.Base(ts.Filepath), filepath.Ext(ts.Filepath))
-		filename := filepath.Join(v.OutputDir, "test_results_"+fname+"."+v.OutputFormat)
-		if err := os.WriteFile(filename, data, 0o600); err != nil {
-			return fmt.Errorf("Error while creating file %s: %v", filename, err)
+	if v.AggregateReport {
+		testsResult := v.testsResult(cleanedTs)
+		for _, format := range formats {
+			if err := v.writeReport(testsResult, format, filepath.Join(v.OutputDir, "test_results."+format)); err != nil {
+				return err
+			}
 		}
-		v.PrintFunc("Writing file %s\n", filename)
 	}
 
 	if v.HtmlReport {
-		testsResult := &Tests{
-			TestSuites:       cleanedTs,
-			Status:           v.Tests.Status,
-			NbTestsuitesFail: v.Tests.NbTestsuitesFail,
-			NbTestsuitesPass: v.Tests.NbTestsuitesPass,
-			NbTestsuitesSkip: v.Tests.NbTestsuitesSkip,
-			Duration:         v.Tests.Duration,
-			Start:            v.Tests.Start,
-			End:              v.Tests.End,
-		}
-
-		data, err := outputHTML(testsResult)
+		data, err := outputHTML(v.testsResult(cleanedTs))
 		if err != nil {
 			return errors.Wrapf(err, "Error: cannot format output html")
 		}
 		filename := filepath.Join(v.OutputDir, computeOutputFilename("test_results.html"))
-		v.PrintFunc("Writing html file %s\n", filename)
+		v.Print("Writing html file %s\n", filename)
 		if err := os.WriteFile(filename, data, 0o600); err != nil {
 			return errors.Wrapf(err, "Error while creating file %s", filename)
 		}
@@ -142,13 +108,59 @@ func (v *Venom) OutputResult() error {
 	return nil
 }
 
+// outputFormats returns the formats of OutputFormat, a comma separated list such as "xml,json"
+func (v *Venom) outputFormats() ([]string, error) {
+	var formats []string
+	for _, format := range strings.Split(v.OutputFormat, ",") {
+		format = strings.TrimSpace(format)
+		if format == "" || slices.Contains(formats, format) {
+			continue
+		}
+		if format == "html" {
+			return nil, errors.New("Error: you have to use the --html-report flag")
+		}
+		if !slices.Contains(ReportFormats, format) {
+			return nil, fmt.Errorf("unsupported format %q, must be one of %v", format, ReportFormats)
+		}
+		formats = append(formats, format)
+	}
+	return formats, nil
+}
+
+// testsResult returns the result of the run restricted to testSuites
+func (v *Venom) testsResult(testSuites []TestSuite) *Tests {
+	return &Tests{
+		TestSuites:       testSuites,
+		Status:           v.Tests.Status,
+		NbTestsuitesFail: v.Tests.NbTestsuitesFail,
+		NbTestsuitesPass: v.Tests.NbTestsuitesPass,
+		NbTestsuitesSkip: v.Tests.NbTestsuitesSkip,
+		Duration:         v.Tests.Duration,
+		Start:            v.Tests.Start,
+		End:              v.Tests.End,
+		Shard:            v.Tests.Shard,
+	}
+}
+
+func (v *Venom) writeReport(tests *Tests, format, filename string) error {
+	data, err := FormatReport(tests, format, v.Verbose)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		return fmt.Errorf("Error while creating file %s: %v", filename, err)
+	}
+	v.Print("Writing file %s\n", filename)
+	return nil
+}
+
 func outputTapFormat(tests Tests) ([]byte, error) {
 	tapValue := tap.New()
 	buf := new(bytes.Buffer)
 	tapValue.Writer = buf
 	var total int
 	for _, ts := range tests.TestSuites {
-		for _, tc := range ts.TestCases {
+		for _, tc := range ts.allTestCases() {
 			total++
 			name := ts.Name + " / " + tc.Name
 			if len(tc.Skipped) > 0 {
@@ -156,16 +168,24 @@ func outputTapFormat(tests Tests) ([]byte, error) {
 				continue
 			}
 
+			var errs []string
 			for _, testStepResult := range tc.TestStepResults {
-				if len(testStepResult.Errors) > 0 {
-					tapValue.Fail(name)
-					for _, e := range testStepResult.Errors {
-						tapValue.Diagnosticf("Error: %s", e.Value)
-					}
-					continue
+				for _, e := range testStepResult.Errors {
+					errs = append(errs, e.Value)
 				}
 			}
-			tapValue.Pass(name)
+			switch {
+			case tc.Status == StatusQuarantined:
+				// the failures of a testcase in quarantine must not fail the consumers of the report
+				tapValue.Skip(1, fmt.Sprintf("quarantined (%s) %s", tc.Quarantine.Status, name))
+			case len(errs) > 0:
+				tapValue.Fail(name)
+			default:
+				tapValue.Pass(name)
+			}
+			for _, e := range errs {
+				tapValue.Diagnosticf("Error: %s", e)
+			}
 		}
 	}
 	tapValue.Header(total)
@@ -178,16 +198,20 @@ func outputXMLFormat(tests Tests, verbose int) ([]byte, error) {
 
 	for _, ts := range tests.TestSuites {
 		tsXML := TestSuiteXML{
-			Name:    ts.Name,
-			Package: ts.Filepath,
-			Time:    fmt.Sprintf("%f", ts.Duration),
+			Name:       ts.Name,
+			Package:    ts.Filepath,
+			Properties: tagsProperties(ts.Tags),
+			Time:       fmt.Sprintf("%f", ts.Duration),
+		}
+		if tests.Shard != nil {
+			tsXML.Properties = append(tsXML.Properties, PropertyXML{Name: "shard", Value: tests.Shard.String()})
 		}
 
-		for _, tc := range ts.TestCases {
+		for _, tc := range ts.allTestCases() {
 			switch tc.Status {
-			case StatusFail:
+			case StatusFail, StatusFlaky:
 				tsXML.Errors++
-			case StatusSkip:
+			case StatusSkip, StatusQuarantined:
 				tsXML.Skipped++
 			}
 			tsXML.Total++
@@ -210,14 +234,25 @@ func outputXMLFormat(tests Tests, verbose int) ([]byte, error) {
 			}
 
 			tcXML := TestCaseXML{
-				Classname: ts.Filename,
-				Errors:    failuresXML,
-				Name:      tc.Name,
-				Skipped:   tc.Skipped,
-				Systemout: systemout,
-				Systemerr: systemerr,
-				Time:      tc.Duration,
-				ID:        tc.ID,
+				Classname:  ts.Filename,
+				Errors:     failuresXML,
+				Name:       tc.Name,
+				Properties: tagsProperties(ts.testCaseTags(tc)),
+				Skipped:    tc.Skipped,
+				Systemout:  systemout,
+				Systemerr:  systemerr,
+				Time:       tc.Duration,
+				ID:         tc.ID,
+			}
+			if tc.PassedOnRerun {
+				tcXML.Properties = append(tcXML.Properties, PropertyXML{Name: "passed_on_rerun", Value: "true"})
+			}
+			if tc.Repeat != nil {
+				tcXML.Errors, tcXML.Properties = repeatXML(tc.Repeat, tcXML.Properties)
+			}
+			if tc.Status == StatusQuarantined {
+				tcXML.Skipped, tcXML.Properties = quarantineXML(tc.Quarantine, tcXML.Errors, tcXML.Properties)
+				tcXML.Errors = nil
 			}
 			tsXML.TestCases = append(tsXML.TestCases, tcXML)
 		}
@@ -233,6 +268,46 @@ func outputXMLFormat(tests Tests, verbose int) ([]byte, error) {
 	return data, nil
 }
 
+// tagsProperties returns the JUnit properties describing the tags
+func tagsProperties(tags []string) []PropertyXML {
+	var properties []PropertyXML
+	for _, t := range tags {
+		properties = append(properties, PropertyXML{Name: "tag", Value: t})
+	}
+	return properties
+}
+
+// repeatXML returns the failures of all the attempts of a repeated testcase,
+// and the JUnit properties describing its pass rate and its attempts
+func repeatXML(repeat *TestCaseRepeat, properties []PropertyXML) ([]FailureXML, []PropertyXML) {
+	failuresXML := []FailureXML{}
+	properties = append(properties, PropertyXML{Name: "pass_rate", Value: fmt.Sprintf("%.2f", repeat.PassRate)})
+	for _, attempt := range repeat.Attempts {
+		properties = append(properties, PropertyXML{Name: "attempt", Value: fmt.Sprintf("%d %s %.3fs", attempt.Number, attempt.Status, attempt.Duration)})
+		for _, e := range attempt.Errors {
+			failuresXML = append(failuresXML, FailureXML{Value: fmt.Sprintf("attempt %d: %s", attempt.Number, e)})
+		}
+	}
+	return failuresXML, properties
+}
+
+// quarantineXML reports a quarantined testcase as skipped, its failures being part of the skipped message,
+// so that it does not fail the JUnit reports
+func quarantineXML(quarantine *TestCaseQuarantine, failures []FailureXML, properties []PropertyXML) ([]Skipped, []PropertyXML) {
+	message := fmt.Sprintf("quarantined: %s", quarantine.Status)
+	for _, f := range failures {
+		message += "\n" + f.Value
+	}
+	properties = append(properties, PropertyXML{Name: "quarantine_status", Value: string(quarantine.Status)})
+	if quarantine.Owner != "" {
+		properties = append(properties, PropertyXML{Name: "quarantine_owner", Value: quarantine.Owner})
+	}
+	if quarantine.Ticket != "" {
+		properties = append(properties, PropertyXML{Name: "quarantine_ticket", Value: quarantine.Ticket})
+	}
+	return []Skipped{{Value: message}}, properties
+}
+
 func appendCleanValue(dest *string, source string) {
 	cleanedValue := strings.ReplaceAll(source, "\x03", "")
 	*dest += cleanedValue
@@ -0,0 +1,78 @@
+package venom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputResultFormats(t *testing.T) {
+	v := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	runTestSuites(t, v, "name: suite 0\ntestcases:\n- name: testcase\n  steps:\n  - type: echo\n    value: foo\n", "name: suite 1")
+
+	v.OutputDir = t.TempDir()
+	v.OutputFormat = "xml, json,xml"
+	v.AggregateReport = true
+	require.NoError(t, v.OutputResult())
+
+	var files []string
+	entries, err := os.ReadDir(v.OutputDir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		files = append(files, e.Name())
+	}
+	require.ElementsMatch(t, []string{
+		"test_results_testsuite_0.xml", "test_results_testsuite_0.json",
+		"test_results_testsuite_1.xml", "test_results_testsuite_1.json",
+		"test_results.xml", "test_results.json",
+	}, files)
+
+	reports, err := ReadReports(filepath.Join(v.OutputDir, "test_results.json"))
+	require.NoError(t, err)
+	require.Len(t, reports[0].TestSuites, 2)
+
+	// the aggregated report does not duplicate the testsuites of a rerun
+	rerun := newTestVenom(t, map[string]Executor{"echo": echoExecutor})
+	rerun.RerunFailed = v.OutputDir
+	require.NoError(t, rerun.Parse(context.Background(), []string{filepath.Dir(reports[0].TestSuites[0].Filepath)}))
+	require.Len(t, rerun.previous.testSuites, 2)
+
+	for _, format := range []string{"html", "csv"} {
+		v.OutputFormat = "xml," + format
+		require.Error(t, v.OutputResult(), format)
+	}
+}
+
+func TestOutputTapFormat(t *testing.T) {
+	failure := func(value string) TestStepResult {
+		return TestStepResult{Errors: []Failure{{Value: value}}}
+	}
+	tests := Tests{TestSuites: []TestSuite{{
+		Name: "suite",
+		TestCases: []TestCase{
+			{TestCaseInput: TestCaseInput{Name: "ok"}, Status: StatusPass, TestStepResults: []TestStepResult{{}}},
+			{TestCaseInput: TestCaseInput{Name: "ko"}, Status: StatusFail, TestStepResults: []TestStepResult{failure("first"), failure("second")}},
+			{
+				TestCaseInput:   TestCaseInput{Name: "flaky"},
+				Status:          StatusQuarantined,
+				Quarantine:      &TestCaseQuarantine{Status: StatusFail},
+				TestStepResults: []TestStepResult{failure("third")},
+			},
+		},
+	}}}
+
+	data, err := outputTapFormat(tests)
+	require.NoError(t, err)
+	require.Equal(t, `ok 1 - suite / ok
+not ok 2 - suite / ko
+# Error: first
+# Error: second
+ok 3 # SKIP quarantined (FAIL) suite / flaky
+# Error: third
+TAP version 13
+1..3
+`, string(data))
+}